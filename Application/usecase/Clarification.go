@@ -0,0 +1,58 @@
+package usecase
+
+import "restaurant-finder/Domain/entity"
+
+// areaFields はエリアに関する項目名です
+var areaFields = map[string]bool{
+	"location":    true,
+	"large_area":  true,
+	"middle_area": true,
+	"small_area":  true,
+}
+
+// clarifiableFields は曖昧な場合に検索せずユーザーに確認する項目です
+var clarifiableFields = map[string]bool{
+	"location":    true,
+	"large_area":  true,
+	"middle_area": true,
+	"small_area":  true,
+	"genre":       true,
+}
+
+// applyOverrides はユーザーが選択したコードで解釈結果を上書きし、解決した曖昧さを取り除きます
+func applyOverrides(params *entity.SearchCriteria, ambiguities []entity.Ambiguity, overrides map[string]string) []entity.Ambiguity {
+	areaChosen := overrides["large_area"] != "" || overrides["middle_area"] != "" || overrides["small_area"] != ""
+	if areaChosen {
+		// 選択されたエリアだけで検索する
+		params.LargeArea = overrides["large_area"]
+		params.MiddleArea = overrides["middle_area"]
+		params.SmallArea = overrides["small_area"]
+	}
+	if code := overrides["genre"]; code != "" {
+		params.Genre = code
+	}
+
+	remaining := make([]entity.Ambiguity, 0, len(ambiguities))
+	for _, a := range ambiguities {
+		if areaFields[a.Field] && areaChosen {
+			continue
+		}
+		if a.Field == "genre" && overrides["genre"] != "" {
+			continue
+		}
+		remaining = append(remaining, a)
+	}
+	return remaining
+}
+
+// splitClarifications は曖昧な項目を、ユーザーに確認するものとそのまま検索するものに分けます
+func splitClarifications(ambiguities []entity.Ambiguity) (clarifications, others []entity.Ambiguity) {
+	for _, a := range ambiguities {
+		if clarifiableFields[a.Field] {
+			clarifications = append(clarifications, a)
+		} else {
+			others = append(others, a)
+		}
+	}
+	return clarifications, others
+}
@@ -0,0 +1,29 @@
+package usecase
+
+import (
+	"math"
+	"sort"
+
+	"restaurant-finder/Domain/entity"
+)
+
+// sortByDistance は現在地から近い順に店舗を並べ、店舗 ID ごとの距離（メートル）を返します
+// 位置が不明な店舗は最後に並べます
+func sortByDistance(shops []entity.Restaurant, lat, lng float64) ([]entity.Restaurant, map[string]int) {
+	distances := make(map[string]int, len(shops))
+	for _, shop := range shops {
+		if entity.ValidLatLng(shop.Lat, shop.Lng) {
+			distances[shop.ID] = int(math.Round(entity.HaversineMeters(lat, lng, shop.Lat, shop.Lng)))
+		}
+	}
+	sorted := append([]entity.Restaurant(nil), shops...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		di, okI := distances[sorted[i].ID]
+		dj, okJ := distances[sorted[j].ID]
+		if okI != okJ {
+			return okI
+		}
+		return di < dj
+	})
+	return sorted, distances
+}
@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"reflect"
+	"testing"
+
+	"restaurant-finder/Domain/entity"
+)
+
+func TestSortByDistance(t *testing.T) {
+	// 新宿駅を中心に、緯度 0.001 度はおよそ 111m
+	const lat, lng = 35.6896, 139.7006
+	shops := []entity.Restaurant{
+		{ID: "far", Lat: lat + 0.010, Lng: lng},
+		{ID: "unknown"},
+		{ID: "near", Lat: lat + 0.001, Lng: lng},
+		{ID: "middle", Lat: lat - 0.005, Lng: lng},
+		{ID: "here", Lat: lat, Lng: lng},
+	}
+
+	sorted, distances := sortByDistance(shops, lat, lng)
+
+	ids := make([]string, len(sorted))
+	for i, shop := range sorted {
+		ids[i] = shop.ID
+	}
+	if want := []string{"here", "near", "middle", "far", "unknown"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("順序 = %v, want %v", ids, want)
+	}
+
+	tests := []struct {
+		id       string
+		min, max int
+		known    bool
+	}{
+		{"here", 0, 0, true},
+		{"near", 105, 117, true},
+		{"middle", 550, 560, true},
+		{"far", 1105, 1120, true},
+		{"unknown", 0, 0, false},
+	}
+	for _, tt := range tests {
+		d, ok := distances[tt.id]
+		if ok != tt.known || d < tt.min || d > tt.max {
+			t.Errorf("距離[%s] = %d (%t), want %d〜%d (%t)", tt.id, d, ok, tt.min, tt.max, tt.known)
+		}
+	}
+	if shops[0].ID != "far" {
+		t.Error("元の一覧の順序が変わりました")
+	}
+}
@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"log"
+	"sort"
+
+	"restaurant-finder/Domain/entity"
+)
+
+const (
+	// facetPageSize はファセット集計のために取得する 1 ページの件数です（HotPepper API の上限）
+	facetPageSize = 100
+	// facetMaxPages はファセット集計のために取得する最大ページ数です
+	facetMaxPages = 3
+)
+
+// collectFacetShops はファセットの集計と絞り込みに使う店舗を取得します
+// 表示した結果に含まれない店舗がある場合は、最大 facetMaxPages ページまで追加で取得します
+// fromStart は店舗が検索結果の先頭からのものか（false の場合は表示したページだけ）を返します
+func (u *GetRestaurantUsecase) collectFacetShops(params *entity.SearchCriteria, result *entity.RestaurantPage) (shops []entity.Restaurant, available int, fromStart bool) {
+	shops = result.Restaurants
+	available = result.Available
+	if available <= len(shops) {
+		return shops, available, result.Start <= 1
+	}
+
+	scanned := make([]entity.Restaurant, 0, min(available, facetPageSize*facetMaxPages))
+	page := params.Clone()
+	page.Count = facetPageSize
+	for i := 0; i < facetMaxPages && len(scanned) < available; i++ {
+		page.Start = 1 + i*facetPageSize
+		scannedPage, err := u.searcher.SearchRestaurants(page)
+		if err != nil {
+			log.Printf("facet scan stopped: start=%d err=%v", page.Start, err)
+			break
+		}
+		if len(scannedPage.Restaurants) == 0 {
+			break
+		}
+		scanned = append(scanned, scannedPage.Restaurants...)
+	}
+	if len(scanned) < len(shops) {
+		// 追加の取得に失敗した場合は表示した結果だけで集計する
+		return shops, available, false
+	}
+	return scanned, available, true
+}
+
+// buildFacets は店舗の一覧からジャンル・予算・こだわり条件ごとの件数を集計します
+func buildFacets(shops []entity.Restaurant, params *entity.SearchCriteria) []entity.Facet {
+	genres := make(map[string]*entity.FacetValue)
+	budgets := make(map[string]*entity.FacetValue)
+	flagCounts := make([]int, len(entity.FacilityOptions))
+
+	for _, shop := range shops {
+		countFacetValue(genres, shop.Genre.Code, shop.Genre.Name, params.Genre)
+		countFacetValue(budgets, shop.PriceRange.Code, shop.PriceRange.Name, params.Budget)
+		for i, flag := range entity.FacilityOptions {
+			if shop.HasFacility(flag.Name) {
+				flagCounts[i]++
+			}
+		}
+	}
+
+	facets := make([]entity.Facet, 0, 3)
+	if len(genres) > 0 {
+		facets = append(facets, entity.Facet{Field: "genre", Label: "ジャンル", Values: sortedFacetValues(genres)})
+	}
+	if len(budgets) > 0 {
+		facets = append(facets, entity.Facet{Field: "budget", Label: "予算", Values: sortedFacetValues(budgets)})
+	}
+
+	flags := make([]entity.FacetValue, 0, len(entity.FacilityOptions))
+	for i, flag := range entity.FacilityOptions {
+		selected := params.RequiresFacility(flag.Name)
+		if flagCounts[i] == 0 && !selected {
+			continue
+		}
+		flags = append(flags, entity.FacetValue{Value: flag.Name, Label: flag.Label, Count: flagCounts[i], Selected: selected})
+	}
+	if len(flags) > 0 {
+		facets = append(facets, entity.Facet{Field: "flags", Label: "こだわり条件", Values: flags})
+	}
+	return facets
+}
+
+// countFacetValue はコードごとの件数を数えます
+func countFacetValue(values map[string]*entity.FacetValue, code, name, selected string) {
+	if code == "" {
+		return
+	}
+	value, ok := values[code]
+	if !ok {
+		value = &entity.FacetValue{Value: code, Label: name, Selected: code == selected}
+		values[code] = value
+	}
+	value.Count++
+}
+
+// sortedFacetValues は件数の多い順に並べます
+func sortedFacetValues(values map[string]*entity.FacetValue) []entity.FacetValue {
+	sorted := make([]entity.FacetValue, 0, len(values))
+	for _, v := range values {
+		sorted = append(sorted, *v)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Count != sorted[j].Count {
+			return sorted[i].Count > sorted[j].Count
+		}
+		return sorted[i].Value < sorted[j].Value
+	})
+	return sorted
+}
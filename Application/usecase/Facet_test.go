@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"restaurant-finder/Domain/entity"
+)
+
+// pagedSearcher は total 件の店舗を Start / Count でページに分けて返す検索のフェイクです
+// failScan を指定した場合は、ファセットの集計のための取得（Count が facetPageSize）を失敗させます
+type pagedSearcher struct {
+	total    int
+	failScan bool
+}
+
+func (f *pagedSearcher) SearchRestaurants(criteria *entity.SearchCriteria) (*entity.RestaurantPage, error) {
+	if f.failScan && criteria.Count == facetPageSize {
+		return nil, errors.New("upstream unavailable")
+	}
+	start := max(criteria.Start, 1)
+	page := &entity.RestaurantPage{Available: f.total, Start: start}
+	for i := start; i < start+criteria.Count && i <= f.total; i++ {
+		// 偶数番目の店舗は 4 席、奇数番目の店舗は 20 席
+		capacity := 20
+		if i%2 == 0 {
+			capacity = 4
+		}
+		page.Restaurants = append(page.Restaurants, entity.Restaurant{ID: fmt.Sprintf("J%d", i), Capacity: capacity})
+	}
+	return page, nil
+}
+
+func TestRunSearchPagesWithinFilteredShops(t *testing.T) {
+	u := &GetRestaurantUsecase{searcher: &pagedSearcher{total: 150}}
+	params := &entity.SearchCriteria{PartyCapacity: 10, Start: 21, Count: 10}
+	outcome, err := u.runSearch(params, searchFilters{}, &entity.MappingTrace{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := outcome.page
+	// 席数の足りる 75 件のうち 21 件目から
+	if page.Available != 75 || page.Start != 21 || len(page.Restaurants) != 10 {
+		t.Fatalf("page = available %d start %d returned %d", page.Available, page.Start, len(page.Restaurants))
+	}
+	if page.Restaurants[0].ID != "J41" {
+		t.Errorf("先頭の店舗 = %s, want J41", page.Restaurants[0].ID)
+	}
+}
+
+func TestRunSearchFallsBackToProviderPageWhenScanFails(t *testing.T) {
+	u := &GetRestaurantUsecase{searcher: &pagedSearcher{total: 150, failScan: true}}
+	params := &entity.SearchCriteria{PartyCapacity: 10, Start: 21, Count: 10}
+	outcome, err := u.runSearch(params, searchFilters{}, &entity.MappingTrace{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := outcome.page
+	// 取得できた表示中のページをそのまま返す
+	if page.Available != 150 || page.Start != 21 || len(page.Restaurants) != 10 || page.Restaurants[0].ID != "J21" {
+		t.Fatalf("page = available %d start %d restaurants %+v", page.Available, page.Start, page.Restaurants)
+	}
+}
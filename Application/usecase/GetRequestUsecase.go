@@ -1,77 +1,469 @@
 package usecase
 
 import (
-	"os"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
 	"restaurant-finder/Domain/entity"
-	api "restaurant-finder/Infrastructure/api"
+	"restaurant-finder/Domain/repository"
+)
+
+// LLM を呼ぶ前に確保する 1 回あたりの見積もり額（USD）です
+// GPT-4o で抽出は 2,000 トークン程度、説明の生成は店舗の一覧を含めて 5,000 トークン程度を想定しています
+const (
+	estimatedExtractionCostUSD = 0.01
+	estimatedSummaryCostUSD    = 0.02
 )
 
-// GetRestaurantUsecase HotPepperAPIを使用してレストラン検索を行うユースケース
+// GetRestaurantUsecase クエリを検索条件に変換してレストラン検索を行うユースケース
 type GetRestaurantUsecase struct {
-	hotPepperClient *api.HotPepperAPIClient
-	openaiGenerator *api.OpenAIGenerator
+	searcher     repository.RestaurantSearcher
+	interpreter  repository.QueryInterpreter
+	summarizer   repository.ResultSummarizer
+	usageTracker repository.UsageRepository
+	inputGuard   *InputGuard
+	stations     repository.StationRepository
+	masterData   repository.MasterDataRepository
+	conditions   repository.ConditionExtractor
 }
 
 // GetRestaurantResult は検索結果と自然言語説明を含む構造体です
 type GetRestaurantResult struct {
-	Response           *entity.HotPepperResponse
+	// Page は表示するページの店舗と、条件に一致する店舗の総数です
+	Page               *entity.RestaurantPage
 	NaturalDescription string
-	SearchParams       *entity.HotPepperRequestParams
+	SearchParams       *entity.SearchCriteria
+	// TokenUsage はこの検索で消費した LLM のトークン数と推定コストです
+	TokenUsage entity.TokenUsage
+	// LLMSkipped は 1 日の利用上限を超えたため LLM を使わなかったことを示します
+	LLMSkipped bool
+	// ExtractionTier は検索条件を解釈したモデル名、または "cache" / "keyword" です
+	ExtractionTier string
+	// SummaryTier は説明を生成したモデル名です（生成しなかった場合は空）
+	SummaryTier string
+	// Language はクエリの言語です
+	Language string
+	// Ambiguities は候補が一意に決まらず、最上位の候補で検索した項目です
+	Ambiguities []entity.Ambiguity
+	// NeedsClarification はエリアやジャンルが一意に決まらず、検索せずにユーザーの選択を待っていることを示します
+	NeedsClarification bool
+	// Clarifications はユーザーに選択してもらう項目と候補です
+	Clarifications []entity.Ambiguity
+	// Trace はクエリが検索パラメータに変換されるまでの記録です（explain モード用）
+	Trace *entity.MappingTrace
+	// Relaxations は結果が 0 件だったために緩めた条件です（SearchParams は緩めた後の条件です）
+	Relaxations []entity.Relaxation
+	// Facets は絞り込み用のジャンル・予算・こだわり条件ごとの件数です
+	Facets []entity.Facet
+	// FacetScanned はファセットの集計に使った店舗数、FacetTotal は該当する店舗の総数です
+	FacetScanned int
+	FacetTotal   int
+	// OpenAt は営業時間で絞り込んだ日時です。UnknownHours は営業時間を解析できず除外した店舗数です
+	OpenAt       *time.Time
+	UnknownHours int
+	// Visit はクエリから読み取った来店の日時・人数・食事の種類です
+	Visit entity.VisitIntent
+	// Distances は現在地や駅を中心に検索した場合の店舗 ID ごとの距離（メートル）です
+	Distances map[string]int
+	// Origin は距離の基準にした地点の名前（"現在地" / "新宿三丁目駅" / 中間地点）です
+	Origin string
+	// UnknownStations は集合駅のうち駅データに見つからなかった名前です
+	UnknownStations []string
+	// MaxWalkMinutes は駅からの徒歩の上限（分）、SortByWalk は徒歩の時間が短い順に並べたかを示します
+	MaxWalkMinutes int
+	SortByWalk     bool
+}
+
+// searchOutcome は HotPepper の検索（条件の緩和・ファセットの集計・営業時間の絞り込みを含む）の結果です
+type searchOutcome struct {
+	page         *entity.RestaurantPage
+	params       *entity.SearchCriteria
+	relaxations  []entity.Relaxation
+	facets       []entity.Facet
+	facetScanned int
+	facetTotal   int
+	unknownHours int
+	distances    map[string]int
+}
+
+// searchFilters は HotPepper から取得した店舗に対して行う絞り込みと並べ替えです
+type searchFilters struct {
+	// openAt を指定した場合は、その日時に営業している店舗だけを返します
+	openAt *time.Time
+	// maxWalkMinutes を指定した場合は、駅からの徒歩の時間が上限を超える店舗を除きます
+	maxWalkMinutes int
+	// sortByWalk は駅からの徒歩の時間が短い順に並べることを示します
+	sortByWalk bool
+}
+
+// SearchRequest は検索の入力です
+type SearchRequest struct {
+	Prompt string
+	// ClientID は利用量をクライアント別に集計するための識別子です
+	ClientID string
+	// Overrides はユーザーが選択したコードです（キーは "large_area" / "middle_area" / "small_area" / "genre"）
+	Overrides map[string]string
+	// Params は編集済みの検索条件です。指定した場合はクエリを解釈せず、LLM を使わずにこの条件で検索します
+	Params *entity.SearchCriteria
+	// OpenAt を指定した場合は、その日時に営業している店舗だけを返します（「営業中」は現在時刻を指定）
+	OpenAt *time.Time
+	// Lat / Lng はブラウザから取得した現在地です。指定した場合は現在地から近い店舗を探します
+	Lat float64
+	Lng float64
+	// MeetStations は集合する各メンバーの最寄り駅です。2 駅以上指定した場合は中間地点の周辺を探します
+	MeetStations []string
+	// MaxWalkMinutes は駅からの徒歩の上限（分）です。0 の場合はクエリの "駅から5分以内" などから読み取ります
+	MaxWalkMinutes int
+	// SortByWalk を指定した場合は駅からの徒歩の時間が短い順に並べます（徒歩の上限を指定した場合も同じ）
+	SortByWalk bool
+	// Page は表示するページ（1 から）です。0 の場合は 1 ページ目です
+	Page int
 }
 
 // NewGetRestaurantUsecase GetRestaurantUsecaseのコンストラクタ
-func NewGetRestaurantUsecase() *GetRestaurantUsecase {
-	openaiAPIKey := os.Getenv("OPENAI_API_KEY")
+// 依存するリポジトリは main で一度だけ作成して渡します
+func NewGetRestaurantUsecase(
+	searcher repository.RestaurantSearcher,
+	interpreter repository.QueryInterpreter,
+	summarizer repository.ResultSummarizer,
+	usage repository.UsageRepository,
+	stations repository.StationRepository,
+	masterData repository.MasterDataRepository,
+	conditions repository.ConditionExtractor,
+) *GetRestaurantUsecase {
 	return &GetRestaurantUsecase{
-		hotPepperClient: &api.HotPepperAPIClient{},
-		openaiGenerator: api.NewOpenAIGenerator(openaiAPIKey),
+		searcher:     searcher,
+		interpreter:  interpreter,
+		summarizer:   summarizer,
+		usageTracker: usage,
+		inputGuard:   NewInputGuard(),
+		stations:     stations,
+		masterData:   masterData,
+		conditions:   conditions,
 	}
 }
 
-// GetRestaurant ユーザーの入力からレストランを検索する
-func (u *GetRestaurantUsecase) GetRestaurant(prompt string) (*entity.HotPepperResponse, error) {
-	// OpenAIを使用してHotPepperAPIのリクエストパラメータを生成
-	params, err := u.openaiGenerator.GenerateSearchQuery(prompt)
-	if params == nil {
-		return nil, err
+// GetRestaurantWithNaturalLanguage ユーザーの入力からレストランを検索し、自然言語での説明も返す
+// clientID は利用量をクライアント別に集計するための識別子です
+func (u *GetRestaurantUsecase) GetRestaurantWithNaturalLanguage(prompt string, clientID string) (*GetRestaurantResult, error) {
+	return u.Search(SearchRequest{Prompt: prompt, ClientID: clientID})
+}
+
+// Search ユーザーの入力からレストランを検索し、自然言語での説明も返す
+// エリアやジャンルが一意に決まらない場合は検索せず、候補を返してユーザーの選択を待つ
+func (u *GetRestaurantUsecase) Search(req SearchRequest) (*GetRestaurantResult, error) {
+	if req.Params != nil {
+		return u.searchByParams(req)
 	}
+	prompt, clientID := req.Prompt, req.ClientID
 
-	// HotPepperAPIを呼び出してレストラン情報を取得
-	response, err := u.hotPepperClient.GetRestaurants(params)
-	if err != nil {
-		return nil, err
+	// LLMに渡す前に入力を検査する
+	decision := u.inputGuard.Check(clientID, prompt)
+	if !decision.Allowed {
+		return nil, &InputRejectedError{Reason: decision.Reason, Message: refusalMessage(prompt)}
 	}
+	prompt = decision.Sanitized
 
-	return response, nil
-}
+	// 1日の利用上限を超える場合はLLMを使わずに検索する
+	// 同時に検索されても上限を超えないよう、呼び出す前に見積もった額を確保しておく
+	var reserved float64
+	llmSkipped := !u.usageTracker.Reserve(estimatedExtractionCostUSD)
+	if !llmSkipped {
+		reserved = estimatedExtractionCostUSD
+	}
 
-// GetRestaurantWithNaturalLanguage ユーザーの入力からレストランを検索し、自然言語での説明も返す
-func (u *GetRestaurantUsecase) GetRestaurantWithNaturalLanguage(prompt string) (*GetRestaurantResult, error) {
-	// OpenAIを使用してHotPepperAPIのリクエストパラメータを生成
-	params, err := u.openaiGenerator.GenerateSearchQuery(prompt)
-	if params == nil {
+	// クエリを検索条件に変換（上限を超えた場合は LLM を使わない）
+	interpretation, err := u.interpreter.InterpretQuery(prompt, !llmSkipped)
+	if interpretation == nil {
+		u.usageTracker.Record(clientID, reserved, entity.TokenUsage{})
 		return nil, err
 	}
+	params := interpretation.Params
+	totalUsage := interpretation.Usage
+	u.usageTracker.Record(clientID, reserved, interpretation.Usage)
+
+	trace := interpretation.Trace
+	if trace == nil {
+		trace = &entity.MappingTrace{}
+	}
+	if llmSkipped {
+		trace.AddStep("usage", "1日の利用上限を超えたため LLM を使いません")
+	}
 
-	// HotPepperAPIを呼び出してレストラン情報を取得
-	response, err := u.hotPepperClient.GetRestaurants(params)
+	// 日時・人数・食事の種類を読み取り、ランチ・深夜営業・宴会収容人数と営業時間の絞り込みに使う
+	// 「今夜」などの相対的な日付はキャッシュせず、毎回現在時刻から解決する
+	visit := u.conditions.VisitIntent(prompt, time.Now())
+	applyVisitIntent(params, visit)
+	openAt := req.OpenAt
+	if openAt == nil && visit.At != nil {
+		openAt = visit.At
+	}
+	if visit.At != nil || visit.PartySize > 0 || visit.Meal != "" {
+		trace.AddStep("visit", "来店の条件: 日時=%s 人数=%d 食事=%s", formatVisitTime(visit.At), visit.PartySize, visit.Meal)
+	}
+
+	// 集合駅の中間地点・クエリ中の駅・現在地のいずれかを中心に検索する
+	origin, ambiguities, unknownStations := u.applyLocation(params, req, prompt, interpretation.Ambiguities, trace)
+
+	// 「駅から5分以内」のような駅からの徒歩の上限（座標がなくてもアクセスの文章で絞り込む）
+	filters := searchFilters{openAt: openAt, maxWalkMinutes: req.MaxWalkMinutes, sortByWalk: req.SortByWalk}
+	if filters.maxWalkMinutes == 0 {
+		filters.maxWalkMinutes = u.conditions.WalkLimit(prompt)
+	}
+	filters.sortByWalk = filters.sortByWalk || filters.maxWalkMinutes > 0
+
+	// ユーザーが選択したコードで上書きし、まだ曖昧な項目があれば検索せずに確認する
+	ambiguities = applyOverrides(params, ambiguities, req.Overrides)
+	for field, code := range req.Overrides {
+		if code != "" {
+			trace.AddStep("override", "ユーザーの選択: %s = %s", field, code)
+		}
+	}
+	u.reconcileAreas(params, trace)
+	clarifications, ambiguities := splitClarifications(ambiguities)
+	for _, a := range clarifications {
+		log.Printf("needs clarification: field=%s term=%s candidates=%d", a.Field, a.Term, len(a.Candidates))
+	}
+	trace.QueryString = u.describeRequest(params)
+	if len(clarifications) > 0 {
+		trace.AddStep("clarification", "曖昧な項目が %d 件あるため検索せずに確認します", len(clarifications))
+		return &GetRestaurantResult{
+			SearchParams:       params,
+			TokenUsage:         totalUsage,
+			LLMSkipped:         llmSkipped,
+			ExtractionTier:     interpretation.Tier,
+			Language:           interpretation.Language,
+			NeedsClarification: true,
+			Clarifications:     clarifications,
+			Trace:              trace,
+			UnknownStations:    unknownStations,
+		}, nil
+	}
+
+	// レストラン情報を取得（0 件の場合は条件を緩めて再検索）
+	applyPage(params, req.Page)
+	outcome, err := u.runSearch(params, filters, trace)
 	if err != nil {
 		return nil, err
 	}
+	page, params := outcome.page, outcome.params
 
 	// 検索結果を自然言語で説明
-	var naturalDesc string
-	if len(response.Results.Shop) > 0 {
-		naturalDesc, err = u.openaiGenerator.GenerateNaturalLanguageResponse(prompt, response.Results.Shop, params)
-		if err != nil {
-			// 自然言語説明の生成に失敗しても検索結果は返す
-			naturalDesc = ""
+	// 利用上限を超えた場合（抽出で上限に達した場合を含む）は説明を生成しない
+	var naturalDesc, summaryTier string
+	if len(page.Restaurants) > 0 && !llmSkipped && u.usageTracker.Reserve(estimatedSummaryCostUSD) {
+		summary, err := u.summarizer.Summarize(prompt, page.Restaurants, params)
+		var usage entity.TokenUsage
+		if summary != nil {
+			usage = summary.Usage
+			totalUsage = totalUsage.Add(summary.Usage)
+		}
+		u.usageTracker.Record(clientID, estimatedSummaryCostUSD, usage)
+		// 自然言語説明の生成に失敗しても検索結果は返す
+		if err == nil {
+			naturalDesc = summary.Text
+			summaryTier = summary.Tier
 		}
 	}
 
 	return &GetRestaurantResult{
-		Response:           response,
+		Page:               page,
 		NaturalDescription: naturalDesc,
 		SearchParams:       params,
+		TokenUsage:         totalUsage,
+		LLMSkipped:         llmSkipped,
+		ExtractionTier:     interpretation.Tier,
+		SummaryTier:        summaryTier,
+		Language:           interpretation.Language,
+		Ambiguities:        ambiguities,
+		Trace:              trace,
+		Relaxations:        outcome.relaxations,
+		Facets:             outcome.facets,
+		FacetScanned:       outcome.facetScanned,
+		FacetTotal:         outcome.facetTotal,
+		OpenAt:             openAt,
+		UnknownHours:       outcome.unknownHours,
+		Visit:              visit,
+		Distances:          outcome.distances,
+		Origin:             origin,
+		UnknownStations:    unknownStations,
+		MaxWalkMinutes:     filters.maxWalkMinutes,
+		SortByWalk:         filters.sortByWalk,
+	}, nil
+}
+
+// searchByParams は編集済みの検索条件でそのまま検索します（クエリの解釈と説明の生成に LLM を使いません）
+func (u *GetRestaurantUsecase) searchByParams(req SearchRequest) (*GetRestaurantResult, error) {
+	params := req.Params.Clone()
+	// キーワードは LLM に渡さないが、HotPepper に送る前に制御文字と長さだけ整える
+	params.Keyword = strings.Join(strings.Fields(stripControlChars(params.Keyword)), " ")
+	if utf8.RuneCountInString(params.Keyword) > maxQueryLength {
+		params.Keyword = string([]rune(params.Keyword)[:maxQueryLength])
+	}
+	if params.Count <= 0 {
+		params.Count = 10
+	}
+	applyPage(params, req.Page)
+
+	u.reconcileAreas(params, nil)
+	if !entity.ValidLatLng(params.Lat, params.Lng) {
+		params.Lat, params.Lng, params.RadiusMeters = 0, 0, 0
+	} else if params.RadiusMeters == 0 {
+		params.RadiusMeters = entity.DefaultRadiusMeters
+	}
+
+	trace := &entity.MappingTrace{}
+	trace.AddStep("params", "編集された検索条件で検索します（LLM を使いません）")
+	trace.QueryString = u.describeRequest(params)
+
+	filters := searchFilters{
+		openAt:         req.OpenAt,
+		maxWalkMinutes: req.MaxWalkMinutes,
+		sortByWalk:     req.SortByWalk,
+	}
+	outcome, err := u.runSearch(params, filters, trace)
+	if err != nil {
+		return nil, err
+	}
+
+	language := entity.LanguageJapanese
+	if params.RequiresFacility(entity.FacilityEnglish) {
+		language = entity.LanguageEnglish
+	}
+	return &GetRestaurantResult{
+		Page:           outcome.page,
+		SearchParams:   outcome.params,
+		ExtractionTier: entity.InterpretationTierParams,
+		Language:       language,
+		Trace:          trace,
+		Relaxations:    outcome.relaxations,
+		Facets:         outcome.facets,
+		FacetScanned:   outcome.facetScanned,
+		FacetTotal:     outcome.facetTotal,
+		OpenAt:         req.OpenAt,
+		UnknownHours:   outcome.unknownHours,
+		Distances:      outcome.distances,
+		MaxWalkMinutes: filters.maxWalkMinutes,
+		SortByWalk:     filters.sortByWalk,
 	}, nil
 }
+
+// reconcileAreas は上位のエリアと整合しない下位のエリアを検索条件から取り除きます
+// 地名・同義語・ユーザーの選択から別々に決まったエリアが、別の大区分・中区分に属する場合に使います
+func (u *GetRestaurantUsecase) reconcileAreas(params *entity.SearchCriteria, trace *entity.MappingTrace) {
+	master, err := u.masterData.MasterData()
+	if err != nil {
+		log.Printf("master data unavailable: %v", err)
+	}
+	if master == nil {
+		return
+	}
+	middle, small := params.MiddleArea, params.SmallArea
+	master.ReconcileAreas(params)
+	if trace != nil && (params.MiddleArea != middle || params.SmallArea != small) {
+		trace.AddStep("area", "大区分と整合しないエリアを除外: middle_area %s→%s small_area %s→%s", middle, params.MiddleArea, small, params.SmallArea)
+	}
+}
+
+// runSearch はレストランを検索し、0 件の場合は条件を緩め、ファセットを集計します
+// 営業日時を指定した場合は、取得した店舗をその日時に営業しているものに絞り込みます
+// 人数を指定した場合は、席数が足りない店舗を除いて席数の近い順に並べます
+// 駅からの徒歩の上限を指定した場合は、上限を超える店舗を除いて徒歩の時間が短い順に並べます
+// 現在地を指定した場合は、現在地から近い順に並べます（人数・徒歩の時間による並べ替えより優先します）
+func (u *GetRestaurantUsecase) runSearch(params *entity.SearchCriteria, filters searchFilters, trace *entity.MappingTrace) (*searchOutcome, error) {
+	page, params, relaxations, err := u.searchWithRelaxation(params, trace)
+	if err != nil {
+		return nil, err
+	}
+	shops, total, fromStart := u.collectFacetShops(params, page)
+	outcome := &searchOutcome{
+		page:         page,
+		params:       params,
+		relaxations:  relaxations,
+		facetScanned: len(shops),
+		facetTotal:   total,
+	}
+
+	postFilter := filters.openAt != nil || params.PartyCapacity > 0 || filters.maxWalkMinutes > 0 ||
+		filters.sortByWalk || entity.ValidLatLng(params.Lat, params.Lng)
+	if postFilter && !fromStart {
+		// 先頭からの店舗を取得できなかった場合は、開始位置がずれないよう提供元のページをそのまま返す
+		trace.AddStep("post_filter", "絞り込み用の店舗を取得できなかったため、絞り込まずに %d 件目からを表示します", page.Start)
+		outcome.facets = buildFacets(shops, params)
+		return outcome, nil
+	}
+	if postFilter && len(shops) < total {
+		trace.AddStep("post_filter", "上位 %d 件（全 %d 件）から絞り込みます", len(shops), total)
+	}
+
+	if openAt := filters.openAt; openAt != nil {
+		open, unknown := filterOpenAt(shops, *openAt)
+		trace.AddStep("open_at", "%s に営業中の店舗で絞り込み: %d 件中 %d 件（営業時間不明 %d 件）",
+			openAt.In(entity.JST).Format("2006-01-02 15:04"), len(shops), len(open), unknown)
+		shops = open
+		outcome.unknownHours = unknown
+	}
+	if params.PartyCapacity > 0 {
+		ranked, excluded := filterByCapacity(shops, params.PartyCapacity)
+		trace.AddStep("capacity", "%d 名に対して席数が足りない店舗を除外: %d 件", params.PartyCapacity, excluded)
+		shops = ranked
+	}
+	if filters.maxWalkMinutes > 0 {
+		kept, excluded := filterByWalk(shops, filters.maxWalkMinutes)
+		trace.AddStep("walk", "駅から徒歩 %d 分を超える店舗を除外: %d 件", filters.maxWalkMinutes, excluded)
+		shops = kept
+	}
+	if filters.sortByWalk {
+		shops = sortByWalk(shops)
+		trace.AddStep("walk", "駅からの徒歩の時間が短い順に並べ替え")
+	}
+	if entity.ValidLatLng(params.Lat, params.Lng) {
+		shops, outcome.distances = sortByDistance(shops, params.Lat, params.Lng)
+		trace.AddStep("distance", "現在地から近い順に並べ替え: %d 件", len(shops))
+	}
+
+	if postFilter {
+		// 絞り込み・並べ替えた店舗から表示するページの分を返す（件数は取得した店舗のうち条件を満たす数）
+		count := params.Count
+		if count <= 0 {
+			count = 10
+		}
+		offset := min(max(params.Start-1, 0), len(shops))
+		page.Restaurants = shops[offset:min(offset+count, len(shops))]
+		page.Available = len(shops)
+		page.Start = offset + 1
+	}
+
+	outcome.facets = buildFacets(shops, params)
+	return outcome, nil
+}
+
+// describeRequest は検索条件を提供元に送るリクエストの形で返します（提供元が対応していない場合は空）
+func (u *GetRestaurantUsecase) describeRequest(params *entity.SearchCriteria) string {
+	if describer, ok := u.searcher.(repository.RequestDescriber); ok {
+		return describer.DescribeRequest(params)
+	}
+	return ""
+}
+
+// applyPage は表示するページ（1 から）を検索の開始位置にします
+func applyPage(params *entity.SearchCriteria, page int) {
+	if page <= 1 {
+		return
+	}
+	count := params.Count
+	if count <= 0 {
+		count = 10
+	}
+	params.Start = (page-1)*count + 1
+}
+
+// formatVisitTime は来店日時を記録用の文字列にします
+func formatVisitTime(at *time.Time) string {
+	if at == nil {
+		return "指定なし"
+	}
+	return at.In(entity.JST).Format("2006-01-02 15:04")
+}
@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"restaurant-finder/Domain/entity"
+)
+
+// fakeInterpreter は固定の解釈結果を返すクエリ解釈のフェイクです
+type fakeInterpreter struct {
+	params *entity.SearchCriteria
+}
+
+func (f fakeInterpreter) InterpretQuery(prompt string, useLLM bool) (*entity.QueryInterpretation, error) {
+	return &entity.QueryInterpretation{Params: f.params.Clone(), Tier: entity.InterpretationTierKeyword}, nil
+}
+
+// fakeUsage は上限に達した状態を返す利用量のフェイクです（LLM を使わずに検索します）
+type fakeUsage struct{}
+
+func (fakeUsage) Reserve(estimatedCostUSD float64) bool { return false }
+
+func (fakeUsage) Record(clientID string, reservedCostUSD float64, usage entity.TokenUsage) {}
+
+// fakeConditions は来店の条件や距離の表現を読み取らない条件抽出のフェイクです
+type fakeConditions struct {
+	rangeCode int
+	midpoint  bool
+}
+
+func (f fakeConditions) VisitIntent(text string, now time.Time) entity.VisitIntent {
+	return entity.VisitIntent{}
+}
+
+func (f fakeConditions) Range(text string) int { return f.rangeCode }
+
+func (f fakeConditions) WalkLimit(text string) int { return 0 }
+
+func (f fakeConditions) WantsMidpoint(text string) bool { return f.midpoint }
+
+// fakeStations は与えた駅だけを探す駅のフェイクです
+type fakeStations []entity.Station
+
+func (f fakeStations) Find(name string) (entity.Station, bool) {
+	for _, station := range f {
+		if station.Name == strings.TrimSuffix(name, "駅") {
+			return station, true
+		}
+	}
+	return entity.Station{}, false
+}
+
+func (f fakeStations) Mentions(text string, requireSuffix bool) []entity.Station {
+	mentioned := make([]entity.Station, 0)
+	for _, station := range f {
+		name := station.Name
+		if requireSuffix {
+			name += "駅"
+		}
+		if strings.Contains(text, name) {
+			mentioned = append(mentioned, station)
+		}
+	}
+	return mentioned
+}
+
+func (f fakeStations) Nearest(lat, lng float64) (entity.Station, bool) {
+	var nearest entity.Station
+	best := math.Inf(1)
+	for _, station := range f {
+		if d := math.Hypot(station.Lat-lat, station.Lng-lng); d < best {
+			nearest, best = station, d
+		}
+	}
+	return nearest, len(f) > 0
+}
+
+func TestSearchReconcilesConflictingAreas(t *testing.T) {
+	master := &entity.MasterData{
+		MiddleAreas: []entity.MasterOption{
+			{Code: "Y001", Name: "渋谷・恵比寿", ParentCode: "Z011"},
+			{Code: "Y300", Name: "キタ", ParentCode: "Z023"},
+		},
+		SmallAreas: []entity.MasterOption{
+			{Code: "X001", Name: "渋谷", ParentCode: "Y001"},
+			{Code: "X300", Name: "梅田", ParentCode: "Y300"},
+		},
+	}
+	tests := []struct {
+		name      string
+		params    entity.SearchCriteria
+		overrides map[string]string
+		want      [3]string
+	}{
+		{
+			name:   "整合するエリア",
+			params: entity.SearchCriteria{LargeArea: "Z011", MiddleArea: "Y001", SmallArea: "X001"},
+			want:   [3]string{"Z011", "Y001", "X001"},
+		},
+		{
+			name:   "同義語で別の大区分の中区分が決まった",
+			params: entity.SearchCriteria{LargeArea: "Z011", MiddleArea: "Y300", SmallArea: "X001"},
+			want:   [3]string{"Z011", "", "X001"},
+		},
+		{
+			name:   "別の中区分の小区分",
+			params: entity.SearchCriteria{LargeArea: "Z011", MiddleArea: "Y001", SmallArea: "X300"},
+			want:   [3]string{"Z011", "Y001", ""},
+		},
+		{
+			name:      "ユーザーが別の大区分の小区分を選択",
+			params:    entity.SearchCriteria{LargeArea: "Z011", MiddleArea: "Y001", SmallArea: "X001"},
+			overrides: map[string]string{"large_area": "Z011", "small_area": "X300"},
+			want:      [3]string{"Z011", "", ""},
+		},
+	}
+	for _, tt := range tests {
+		tt.params.Keyword = "居酒屋"
+		searcher := &fakeSearcher{found: func(*entity.SearchCriteria) bool { return true }}
+		u := NewGetRestaurantUsecase(searcher, fakeInterpreter{params: &tt.params}, nil, fakeUsage{}, fakeStations{},
+			fakeMasterData{master: master}, fakeConditions{})
+		result, err := u.Search(SearchRequest{Prompt: "居酒屋", Overrides: tt.overrides})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if len(searcher.calls) == 0 {
+			t.Fatalf("%s: 検索していません", tt.name)
+		}
+		got := searcher.calls[0]
+		if [3]string{got.LargeArea, got.MiddleArea, got.SmallArea} != tt.want {
+			t.Errorf("%s: 検索したエリア = (%q, %q, %q), want %q", tt.name, got.LargeArea, got.MiddleArea, got.SmallArea, tt.want)
+		}
+		if p := result.SearchParams; [3]string{p.LargeArea, p.MiddleArea, p.SmallArea} != tt.want {
+			t.Errorf("%s: SearchParams のエリア = (%q, %q, %q), want %q", tt.name, p.LargeArea, p.MiddleArea, p.SmallArea, tt.want)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"restaurant-finder/Domain/entity"
+)
+
+// maxQueryLength は検索クエリの最大文字数です
+const maxQueryLength = 200
+
+// refusalMessages は検索と関係のない入力を断るときのメッセージです（クエリの言語ごと）
+var refusalMessages = map[string]string{
+	entity.LanguageJapanese: "申し訳ありませんが、お店探しに関するご要望のみお受けしています。エリアや料理のジャンル、ご予算などを入力してください。",
+	entity.LanguageEnglish:  "Sorry, we can only help you find restaurants. Please enter an area, a type of cuisine, a budget and so on.",
+}
+
+// refusalMessage はクエリの言語で断るときのメッセージを返します（日本語以外で用意していない言語は英語）
+func refusalMessage(query string) string {
+	if message, ok := refusalMessages[entity.DetectLanguage(query)]; ok {
+		return message
+	}
+	return refusalMessages[entity.LanguageEnglish]
+}
+
+// injectionPatterns はプロンプトインジェクションとみなす表現です
+var injectionPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)ignore\s+(all\s+)?(the\s+)?(previous|above|prior)\s+(instructions|prompts?|rules)`),
+	regexp.MustCompile(`(?i)disregard\s+(all\s+)?(the\s+)?(previous|above|prior)`),
+	regexp.MustCompile(`(?i)(reveal|show|print|repeat)\s+(your|the)\s+(system\s+)?(prompt|instructions)`),
+	regexp.MustCompile(`(?i)you\s+are\s+now\s+`),
+	regexp.MustCompile(`(?i)\b(jailbreak|dan\s+mode|developer\s+mode)\b`),
+	regexp.MustCompile(`(?i)^\s*(system|assistant)\s*:`),
+	regexp.MustCompile(`(以前|前|上記|これまで)の(指示|命令|プロンプト|ルール)を(無視|忘れ)`),
+	regexp.MustCompile(`(システム)?プロンプトを(表示|教え|出力)`),
+	regexp.MustCompile(`(あなたは今から|今からあなたは).*(として|になりきって)`),
+}
+
+// offTopicPatterns は検索と関係のない依頼や不適切な入力とみなす表現です
+var offTopicPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\b(write|generate)\s+(me\s+)?(a\s+)?(code|program|script|essay|poem)\b`),
+	regexp.MustCompile(`(コード|プログラム|作文|小説|詩)を(書いて|作って|生成)`),
+	regexp.MustCompile(`(?i)\b(password|api[\s_-]?key|credit\s+card)\b`),
+	regexp.MustCompile(`(パスワード|APIキー|クレジットカード番号)`),
+	// "killer ramen" や "kill time" のような表現があるため、kill は人に向けた表現だけを断る
+	regexp.MustCompile(`(?i)\b(kill|murder)\s+(my(self)?|yourself|him|her|them|you|someone|somebody|people|everyone)\b`),
+	regexp.MustCompile(`(?i)\bsuicide\b`),
+	regexp.MustCompile(`(殺す|死ね|自殺)`),
+	// "爆弾おにぎり" や "bomb burger" のようなメニュー名があるため、爆弾は作る・仕掛ける表現だけを断る
+	regexp.MustCompile(`(?i)\b(make|build|plant)\s+(a\s+)?bombs?\b`),
+	regexp.MustCompile(`爆弾(の(作り方|材料)|を(作|仕掛|しかけ))`),
+}
+
+// GuardDecision は入力ガードの判定結果です
+type GuardDecision struct {
+	Allowed bool
+	// Sanitized は制御文字の除去や長さの切り詰めを行った後のクエリです
+	Sanitized string
+	// Reason は判定の理由です（ログ用）
+	Reason string
+}
+
+// InputRejectedError は入力ガードで検索を断ったことを示すエラーです
+type InputRejectedError struct {
+	Reason  string
+	Message string
+}
+
+func (e *InputRejectedError) Error() string {
+	return fmt.Sprintf("入力が拒否されました: %s", e.Reason)
+}
+
+// InputGuard は LLM に渡す前に検索クエリを検査します
+type InputGuard struct {
+	maxLength int
+}
+
+// NewInputGuard は新しい InputGuard を作成します
+func NewInputGuard() *InputGuard {
+	return &InputGuard{maxLength: maxQueryLength}
+}
+
+// Check はクエリを正規化し、インジェクションや検索と関係のない入力を検出します
+func (g *InputGuard) Check(clientID, query string) GuardDecision {
+	sanitized := stripControlChars(query)
+	sanitized = strings.Join(strings.Fields(sanitized), " ")
+
+	decision := GuardDecision{Allowed: true, Sanitized: sanitized, Reason: "ok"}
+	switch {
+	case sanitized == "":
+		decision = GuardDecision{Allowed: false, Reason: "empty"}
+	case matchesAny(injectionPatterns, sanitized):
+		decision = GuardDecision{Allowed: false, Reason: "prompt_injection"}
+	case matchesAny(offTopicPatterns, sanitized):
+		decision = GuardDecision{Allowed: false, Reason: "off_topic"}
+	case utf8.RuneCountInString(sanitized) > g.maxLength:
+		decision.Sanitized = string([]rune(sanitized)[:g.maxLength])
+		decision.Reason = "truncated"
+	}
+
+	log.Printf("input guard: client=%s allowed=%t reason=%s length=%d",
+		clientID, decision.Allowed, decision.Reason, utf8.RuneCountInString(query))
+	return decision
+}
+
+// stripControlChars は改行やタブを空白に置き換え、その他の制御文字・不可視文字を除去します
+func stripControlChars(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '\n' || r == '\r' || r == '\t':
+			return ' '
+		case unicode.IsControl(r), unicode.Is(unicode.Cf, r):
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// matchesAny は文字列がいずれかのパターンに一致するかを返します
+func matchesAny(patterns []*regexp.Regexp, s string) bool {
+	for _, p := range patterns {
+		if p.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
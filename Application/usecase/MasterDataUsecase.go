@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"errors"
+
+	"restaurant-finder/Domain/entity"
+	"restaurant-finder/Domain/repository"
+)
+
+// ErrUnknownAreaLevel はエリアの区分が "large" / "middle" / "small" 以外であることを示すエラーです
+var ErrUnknownAreaLevel = errors.New("エリアの区分は large / middle / small のいずれかを指定してください")
+
+// MasterDataUsecase エリア・ジャンル・予算のマスタデータを参照するユースケース
+type MasterDataUsecase struct {
+	masterData repository.MasterDataRepository
+}
+
+// NewMasterDataUsecase MasterDataUsecaseのコンストラクタ
+func NewMasterDataUsecase(masterData repository.MasterDataRepository) *MasterDataUsecase {
+	return &MasterDataUsecase{masterData: masterData}
+}
+
+// MasterData はエリア・ジャンル・予算の選択肢をまとめて返します（読み込めない場合も空の選択肢を返します）
+func (u *MasterDataUsecase) MasterData() (*entity.MasterData, error) {
+	master, err := u.masterData.MasterData()
+	if master == nil {
+		master = &entity.MasterData{}
+	}
+	return master, err
+}
+
+// Areas は指定した区分のエリアを返します。parent を指定した場合はその上位のエリアに含まれるものだけを返します
+func (u *MasterDataUsecase) Areas(level, parent string) ([]entity.MasterOption, error) {
+	master, err := u.masterData.MasterData()
+	if err != nil {
+		return nil, err
+	}
+	switch level {
+	case "", "large":
+		return master.LargeAreas, nil
+	case "middle":
+		if parent == "" {
+			return master.MiddleAreas, nil
+		}
+		return master.MiddleAreasIn(parent), nil
+	case "small":
+		if parent == "" {
+			return master.SmallAreas, nil
+		}
+		return master.SmallAreasIn(parent), nil
+	}
+	return nil, ErrUnknownAreaLevel
+}
+
+// Genres はジャンルの一覧を返します
+func (u *MasterDataUsecase) Genres() ([]entity.MasterOption, error) {
+	master, err := u.masterData.MasterData()
+	if err != nil {
+		return nil, err
+	}
+	return master.Genres, nil
+}
+
+// Budgets は予算の一覧を返します
+func (u *MasterDataUsecase) Budgets() ([]entity.MasterOption, error) {
+	master, err := u.masterData.MasterData()
+	if err != nil {
+		return nil, err
+	}
+	return master.Budgets, nil
+}
@@ -0,0 +1,24 @@
+package usecase
+
+import (
+	"time"
+
+	"restaurant-finder/Domain/entity"
+)
+
+// filterOpenAt は指定した日時に営業している店舗だけを返します
+// 営業時間を解析できなかった店舗は除外し、その件数を unknown として返します
+func filterOpenAt(shops []entity.Restaurant, at time.Time) (open []entity.Restaurant, unknown int) {
+	open = make([]entity.Restaurant, 0, len(shops))
+	for _, shop := range shops {
+		isOpen, known := shop.Hours.IsOpenAt(at)
+		if !known {
+			unknown++
+			continue
+		}
+		if isOpen {
+			open = append(open, shop)
+		}
+	}
+	return open, unknown
+}
@@ -0,0 +1,28 @@
+package usecase
+
+import (
+	"sort"
+
+	"restaurant-finder/Domain/entity"
+)
+
+// filterByCapacity は総席数が人数に満たない店舗を除き、人数に近い席数の店舗から順に並べます
+// 総席数が不明な店舗は除外せず、最後に並べます
+func filterByCapacity(shops []entity.Restaurant, partySize int) (ranked []entity.Restaurant, excluded int) {
+	ranked = make([]entity.Restaurant, 0, len(shops))
+	for _, shop := range shops {
+		if capacity := shop.Capacity; capacity > 0 && capacity < partySize {
+			excluded++
+			continue
+		}
+		ranked = append(ranked, shop)
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		ci, cj := ranked[i].Capacity, ranked[j].Capacity
+		if (ci == 0) != (cj == 0) {
+			return cj == 0
+		}
+		return ci < cj
+	})
+	return ranked, excluded
+}
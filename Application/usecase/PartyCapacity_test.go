@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"reflect"
+	"testing"
+
+	"restaurant-finder/Domain/entity"
+)
+
+func TestFilterByCapacity(t *testing.T) {
+	shops := func(capacities ...int) []entity.Restaurant {
+		list := make([]entity.Restaurant, len(capacities))
+		for i, c := range capacities {
+			list[i] = entity.Restaurant{ID: string(rune('A' + i)), Capacity: c}
+		}
+		return list
+	}
+	ids := func(list []entity.Restaurant) []string {
+		result := make([]string, len(list))
+		for i, shop := range list {
+			result[i] = shop.ID
+		}
+		return result
+	}
+
+	tests := []struct {
+		name      string
+		shops     []entity.Restaurant
+		partySize int
+		want      []string
+		excluded  int
+	}{
+		{"店舗なし", nil, 4, []string{}, 0},
+		{"席数の足りない店舗を除く", shops(2, 10, 3), 4, []string{"B"}, 2},
+		{"席数がちょうど人数の店舗は残す", shops(4, 3), 4, []string{"A"}, 1},
+		{"人数に近い席数から並べる", shops(50, 8, 20), 6, []string{"B", "C", "A"}, 0},
+		{"席数が不明な店舗は最後に並べる", shops(0, 30, 0, 12), 10, []string{"D", "B", "A", "C"}, 0},
+		{"同じ席数は元の順序を保つ", shops(20, 20, 10), 8, []string{"C", "A", "B"}, 0},
+		{"すべての店舗で席数が足りない", shops(2, 3), 40, []string{}, 2},
+	}
+	for _, tt := range tests {
+		ranked, excluded := filterByCapacity(tt.shops, tt.partySize)
+		if got := ids(ranked); !reflect.DeepEqual(got, tt.want) || excluded != tt.excluded {
+			t.Errorf("%s: filterByCapacity() = (%v, %d), want (%v, %d)", tt.name, got, excluded, tt.want, tt.excluded)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package usecase
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"restaurant-finder/Domain/entity"
+)
+
+// relaxationStep は条件を 1 段階緩めます。緩める条件がない場合は nil を返します
+type relaxationStep func(params *entity.SearchCriteria, master *entity.MasterData) *entity.Relaxation
+
+// relaxationSteps は条件を緩める順序です
+// （エリアと重なる現在地 → こだわり条件 → 現在地からの範囲 → 予算 → 小区分を中区分に → 中区分を大区分に）
+var relaxationSteps = []relaxationStep{
+	dropLocationForArea,
+	dropFlags,
+	widenRange,
+	dropBudget,
+	widenSmallArea,
+	widenMiddleArea,
+}
+
+// dropLocationForArea はエリアと現在地の両方が指定されている場合に現在地の条件を外します
+// HotPepper は両方を満たす店舗を返すため、現在地が「新宿」で「渋谷 居酒屋」と検索すると見つかりません
+// クエリで指定したエリアを優先し、暗黙に付いた現在地の方を外します
+func dropLocationForArea(params *entity.SearchCriteria, master *entity.MasterData) *entity.Relaxation {
+	if !entity.ValidLatLng(params.Lat, params.Lng) {
+		return nil
+	}
+	var area string
+	switch {
+	case params.SmallArea != "":
+		area = master.Name("small_area", params.SmallArea)
+	case params.MiddleArea != "":
+		area = master.Name("middle_area", params.MiddleArea)
+	case params.LargeArea != "":
+		area = master.Name("large_area", params.LargeArea)
+	default:
+		return nil
+	}
+	params.Lat, params.Lng, params.RadiusMeters = 0, 0, 0
+	return &entity.Relaxation{
+		Field:       "location",
+		Description: fmt.Sprintf("現在地からの距離の条件を外し、エリア（%s）で検索しました", area),
+	}
+}
+
+// dropFlags はオン・オフの条件と宴会収容人数の条件をすべて外します
+func dropFlags(params *entity.SearchCriteria, master *entity.MasterData) *entity.Relaxation {
+	dropped := make([]string, 0)
+	for _, facility := range entity.FacilityOptions {
+		if params.RequiresFacility(facility.Name) {
+			params.SetFacility(facility.Name, false)
+			dropped = append(dropped, facility.Label)
+		}
+	}
+	if params.PartyCapacity != 0 {
+		dropped = append(dropped, fmt.Sprintf("%d名以上で利用可", params.PartyCapacity))
+		params.PartyCapacity = 0
+	}
+	if len(dropped) == 0 {
+		return nil
+	}
+	return &entity.Relaxation{
+		Field:       "flags",
+		Description: fmt.Sprintf("こだわり条件（%s）を外しました", strings.Join(dropped, "、")),
+	}
+}
+
+// widenRange は現在地からの検索範囲を最大（3000m）に広げます
+func widenRange(params *entity.SearchCriteria, master *entity.MasterData) *entity.Relaxation {
+	widest := entity.RangeMeters(5)
+	if params.RadiusMeters == 0 || params.RadiusMeters >= widest {
+		return nil
+	}
+	from := params.RadiusMeters
+	params.RadiusMeters = widest
+	return &entity.Relaxation{
+		Field:       "range",
+		Description: fmt.Sprintf("現在地からの範囲を%dmから%dmに広げました", from, params.RadiusMeters),
+	}
+}
+
+// dropBudget は予算の条件を外します
+func dropBudget(params *entity.SearchCriteria, master *entity.MasterData) *entity.Relaxation {
+	if params.Budget == "" {
+		return nil
+	}
+	name := master.Name("budget", params.Budget)
+	params.Budget = ""
+	return &entity.Relaxation{
+		Field:       "budget",
+		Description: fmt.Sprintf("予算（%s）の条件を外しました", name),
+	}
+}
+
+// widenSmallArea は小区分の指定を外し、その中区分で検索します
+func widenSmallArea(params *entity.SearchCriteria, master *entity.MasterData) *entity.Relaxation {
+	if params.SmallArea == "" {
+		return nil
+	}
+	middle := params.MiddleArea
+	if middle == "" {
+		middle = master.ParentCode("small_area", params.SmallArea)
+	}
+	if middle == "" {
+		// 上位のエリアがわからない場合はエリアの指定がなくなってしまうため緩めない
+		return nil
+	}
+	from := master.Name("small_area", params.SmallArea)
+	params.SmallArea = ""
+	params.MiddleArea = middle
+	return &entity.Relaxation{
+		Field:       "small_area",
+		Description: fmt.Sprintf("エリアを「%s」から「%s」周辺に広げました", from, master.Name("middle_area", middle)),
+	}
+}
+
+// widenMiddleArea は中区分の指定を外し、その大区分で検索します
+func widenMiddleArea(params *entity.SearchCriteria, master *entity.MasterData) *entity.Relaxation {
+	if params.MiddleArea == "" || params.SmallArea != "" {
+		return nil
+	}
+	large := params.LargeArea
+	if large == "" {
+		large = master.ParentCode("middle_area", params.MiddleArea)
+	}
+	if large == "" {
+		return nil
+	}
+	from := master.Name("middle_area", params.MiddleArea)
+	params.MiddleArea = ""
+	params.LargeArea = large
+	return &entity.Relaxation{
+		Field:       "middle_area",
+		Description: fmt.Sprintf("エリアを「%s」から「%s」全体に広げました", from, master.Name("large_area", large)),
+	}
+}
+
+// searchWithRelaxation は検索結果が 0 件の場合に条件を順に緩めて再検索します
+// 結果が見つかった時点の条件と、緩めた条件を返します。すべて緩めても 0 件の場合は元の条件と結果を返します
+func (u *GetRestaurantUsecase) searchWithRelaxation(params *entity.SearchCriteria, trace *entity.MappingTrace) (*entity.RestaurantPage, *entity.SearchCriteria, []entity.Relaxation, error) {
+	page, err := u.searcher.SearchRestaurants(params)
+	if err != nil || page.Available > 0 {
+		return page, params, nil, err
+	}
+
+	master, err := u.masterData.MasterData()
+	if err != nil {
+		log.Printf("master data unavailable: %v", err)
+	}
+	if master == nil {
+		master = &entity.MasterData{}
+	}
+
+	relaxed := params.Clone()
+	relaxations := make([]entity.Relaxation, 0)
+	for _, step := range relaxationSteps {
+		relaxation := step(relaxed, master)
+		if relaxation == nil {
+			continue
+		}
+		relaxations = append(relaxations, *relaxation)
+		trace.AddStep("relax", "%s", relaxation.Description)
+
+		relaxedPage, err := u.searcher.SearchRestaurants(relaxed)
+		if err != nil {
+			return nil, params, nil, err
+		}
+		if relaxedPage.Available > 0 {
+			log.Printf("relaxed search: steps=%d available=%d", len(relaxations), relaxedPage.Available)
+			trace.QueryString = u.describeRequest(relaxed)
+			return relaxedPage, relaxed, relaxations, nil
+		}
+	}
+
+	trace.AddStep("relax", "条件を緩めても見つかりませんでした")
+	return page, params, nil, nil
+}
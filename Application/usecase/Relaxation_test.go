@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"testing"
+
+	"restaurant-finder/Domain/entity"
+)
+
+// fakeSearcher は条件を満たす場合だけ店舗を返す検索のフェイクです
+type fakeSearcher struct {
+	found func(criteria *entity.SearchCriteria) bool
+	calls []entity.SearchCriteria
+}
+
+func (f *fakeSearcher) SearchRestaurants(criteria *entity.SearchCriteria) (*entity.RestaurantPage, error) {
+	f.calls = append(f.calls, *criteria)
+	if f.found != nil && f.found(criteria) {
+		return &entity.RestaurantPage{Restaurants: []entity.Restaurant{{ID: "J1", Name: "テスト店"}}, Available: 1, Start: 1}, nil
+	}
+	return &entity.RestaurantPage{Start: 1}, nil
+}
+
+// fakeMasterData は固定のマスタデータを返すフェイクです
+type fakeMasterData struct {
+	master *entity.MasterData
+}
+
+func (f fakeMasterData) MasterData() (*entity.MasterData, error) {
+	return f.master, nil
+}
+
+func TestSearchWithRelaxationDropsEnglish(t *testing.T) {
+	searcher := &fakeSearcher{found: func(c *entity.SearchCriteria) bool { return !c.RequiresFacility(entity.FacilityEnglish) }}
+	u := &GetRestaurantUsecase{searcher: searcher, masterData: fakeMasterData{}}
+
+	params := &entity.SearchCriteria{Keyword: "居酒屋", Facilities: []string{entity.FacilityEnglish}, SmallArea: "X001"}
+	page, relaxed, relaxations, err := u.searchWithRelaxation(params, &entity.MappingTrace{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Available != 1 || relaxed.RequiresFacility(entity.FacilityEnglish) {
+		t.Fatalf("english の条件が外れていません: available=%d facilities=%v", page.Available, relaxed.Facilities)
+	}
+	if relaxed.SmallArea != "X001" {
+		t.Errorf("エリアまで緩められました: %q", relaxed.SmallArea)
+	}
+	if len(relaxations) != 1 || relaxations[0].Field != "flags" {
+		t.Errorf("relaxations = %+v", relaxations)
+	}
+	if !params.RequiresFacility(entity.FacilityEnglish) {
+		t.Error("元の検索条件が書き換えられました")
+	}
+}
+
+func TestDropFlags(t *testing.T) {
+	params := &entity.SearchCriteria{Facilities: []string{entity.FacilityLunch, entity.FacilityEnglish}, PartyCapacity: 8}
+	relaxation := dropFlags(params, &entity.MasterData{})
+	if relaxation == nil {
+		t.Fatal("条件が外れていません")
+	}
+	if len(params.Facilities) != 0 || params.PartyCapacity != 0 {
+		t.Errorf("params = %+v", params)
+	}
+	if want := "こだわり条件（ランチあり、英語メニューあり、8名以上で利用可）を外しました"; relaxation.Description != want {
+		t.Errorf("Description = %q, want %q", relaxation.Description, want)
+	}
+	if dropFlags(params, &entity.MasterData{}) != nil {
+		t.Error("外す条件がないのに緩和されました")
+	}
+}
+
+func TestSearchWithRelaxationDropsLocationConflictingWithArea(t *testing.T) {
+	searcher := &fakeSearcher{found: func(c *entity.SearchCriteria) bool { return c.Lat == 0 }}
+	master := &entity.MasterData{SmallAreas: []entity.MasterOption{{Code: "X001", Name: "渋谷"}}}
+	u := &GetRestaurantUsecase{searcher: searcher, masterData: fakeMasterData{master: master}}
+
+	// 現在地は新宿、クエリのエリアは渋谷
+	params := &entity.SearchCriteria{Keyword: "居酒屋", SmallArea: "X001", Lat: 35.6896, Lng: 139.7006, RadiusMeters: 1000}
+	page, relaxed, relaxations, err := u.searchWithRelaxation(params, &entity.MappingTrace{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Available != 1 || relaxed.SmallArea != "X001" || relaxed.Lat != 0 || relaxed.RadiusMeters != 0 {
+		t.Fatalf("現在地の条件が外れていません: available=%d params=%+v", page.Available, relaxed)
+	}
+	if len(relaxations) != 1 || relaxations[0].Field != "location" {
+		t.Fatalf("relaxations = %+v", relaxations)
+	}
+	if want := "現在地からの距離の条件を外し、エリア（渋谷）で検索しました"; relaxations[0].Description != want {
+		t.Errorf("Description = %q, want %q", relaxations[0].Description, want)
+	}
+}
+
+func TestDropLocationForAreaKeepsLocationWithoutArea(t *testing.T) {
+	params := &entity.SearchCriteria{Lat: 35.6896, Lng: 139.7006, RadiusMeters: 500}
+	if relaxation := dropLocationForArea(params, &entity.MasterData{}); relaxation != nil {
+		t.Errorf("エリアの指定がないのに現在地が外れました: %+v", relaxation)
+	}
+	if params.Lat == 0 || params.RadiusMeters != 500 {
+		t.Errorf("params = %+v", params)
+	}
+}
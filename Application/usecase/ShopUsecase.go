@@ -0,0 +1,29 @@
+package usecase
+
+import (
+	"errors"
+	"strings"
+
+	"restaurant-finder/Domain/entity"
+)
+
+// ErrShopNotFound は指定した ID の店舗が見つからないことを示すエラーです
+var ErrShopNotFound = errors.New("店舗が見つかりません")
+
+// GetShop は店舗 ID で 1 件の店舗を取得します
+func (u *GetRestaurantUsecase) GetShop(id string) (*entity.Restaurant, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return nil, ErrShopNotFound
+	}
+	page, err := u.searcher.SearchRestaurants(&entity.SearchCriteria{ID: id, Count: 1})
+	if err != nil {
+		return nil, err
+	}
+	for _, shop := range page.Restaurants {
+		if shop.ID == id {
+			return &shop, nil
+		}
+	}
+	return nil, ErrShopNotFound
+}
@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"strings"
+
+	"restaurant-finder/Domain/entity"
+)
+
+// applyLocation は検索の中心を決めて検索条件に反映し、中心の名前（"新宿三丁目駅" / "現在地" など）を返します
+// 集合駅の中間地点、クエリ中の駅、ブラウザの現在地の順に優先します
+// 駅の位置で検索する場合はエリアの指定とエリアの曖昧さを外します（小区分より駅からの距離の方が正確なため）
+func (u *GetRestaurantUsecase) applyLocation(params *entity.SearchCriteria, req SearchRequest, prompt string, ambiguities []entity.Ambiguity, trace *entity.MappingTrace) (origin string, remaining []entity.Ambiguity, unknownStations []string) {
+	// 集合駅の指定、またはクエリ中の「渋谷と池袋の中間」のような表現
+	members := make([]entity.Station, 0, len(req.MeetStations))
+	for _, name := range req.MeetStations {
+		if station, ok := u.stations.Find(name); ok {
+			members = append(members, station)
+		} else {
+			unknownStations = append(unknownStations, name)
+		}
+	}
+	if len(members) < 2 && u.conditions.WantsMidpoint(prompt) {
+		members = u.stations.Mentions(prompt, false)
+	}
+	if len(members) >= 2 {
+		lat, lng := entity.Midpoint(members)
+		u.setLocation(params, lat, lng, prompt)
+		origin = strings.Join(stationNames(members), "・") + "の中間地点"
+		if nearest, ok := u.stations.Nearest(lat, lng); ok {
+			origin += "（" + nearest.Name + "駅付近）"
+		}
+		trace.AddStep("station", "%s (%g, %g) から %dm 以内を検索します", origin, params.Lat, params.Lng, params.RadiusMeters)
+		return origin, withoutAreas(params, ambiguities), unknownStations
+	}
+
+	// "新宿三丁目駅の近く" のような駅の指定
+	if mentioned := u.stations.Mentions(prompt, true); len(mentioned) > 0 {
+		station := mentioned[0]
+		u.setLocation(params, station.Lat, station.Lng, prompt)
+		origin = station.Name + "駅"
+		trace.AddStep("station", "%s（%s）から %dm 以内を検索します", origin, strings.Join(station.Lines, "・"), params.RadiusMeters)
+		return origin, withoutAreas(params, ambiguities), unknownStations
+	}
+
+	// ブラウザの現在地（「徒歩5分以内」「近く」などの表現から検索範囲を決める）
+	if u.setLocation(params, req.Lat, req.Lng, prompt) {
+		trace.AddStep("location", "現在地 (%g, %g) から %dm 以内を検索します", params.Lat, params.Lng, params.RadiusMeters)
+		return "現在地", ambiguities, unknownStations
+	}
+	if u.conditions.Range(prompt) != 0 {
+		trace.AddStep("location", "現在地が指定されていないため距離の条件は使いません")
+	}
+	return "", ambiguities, unknownStations
+}
+
+// setLocation は検索の中心とクエリの距離の表現を検索条件に反映します
+// 位置が有効でない場合は何もせず false を返します。距離の表現がない場合は entity.DefaultRadiusMeters を使います
+func (u *GetRestaurantUsecase) setLocation(params *entity.SearchCriteria, lat, lng float64, prompt string) bool {
+	if !entity.ValidLatLng(lat, lng) {
+		return false
+	}
+	params.Lat = lat
+	params.Lng = lng
+	if code := u.conditions.Range(prompt); code != 0 {
+		params.RadiusMeters = entity.RangeMeters(code)
+	} else if params.RadiusMeters == 0 {
+		params.RadiusMeters = entity.DefaultRadiusMeters
+	}
+	return true
+}
+
+// withoutAreas はエリアの指定を外し、エリア以外の曖昧さだけを返します
+func withoutAreas(params *entity.SearchCriteria, ambiguities []entity.Ambiguity) []entity.Ambiguity {
+	params.LargeArea, params.MiddleArea, params.SmallArea = "", "", ""
+	remaining := make([]entity.Ambiguity, 0, len(ambiguities))
+	for _, a := range ambiguities {
+		if !areaFields[a.Field] {
+			remaining = append(remaining, a)
+		}
+	}
+	return remaining
+}
+
+// stationNames は駅名に「駅」を付けた一覧を返します
+func stationNames(stations []entity.Station) []string {
+	names := make([]string, len(stations))
+	for i, station := range stations {
+		names[i] = station.Name + "駅"
+	}
+	return names
+}
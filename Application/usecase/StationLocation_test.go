@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"reflect"
+	"testing"
+
+	"restaurant-finder/Domain/entity"
+)
+
+func TestApplyLocation(t *testing.T) {
+	shinjuku := entity.Station{Name: "新宿", Lines: []string{"JR山手線"}, Lat: 35.6896, Lng: 139.7006}
+	shibuya := entity.Station{Name: "渋谷", Lines: []string{"JR山手線"}, Lat: 35.6580, Lng: 139.7016}
+	yoyogi := entity.Station{Name: "代々木", Lines: []string{"JR山手線"}, Lat: 35.6830, Lng: 139.7020}
+	stations := fakeStations{shinjuku, shibuya, yoyogi}
+	midLat, midLng := entity.Midpoint([]entity.Station{shinjuku, shibuya})
+
+	areas := entity.SearchCriteria{LargeArea: "Z011", MiddleArea: "Y001", SmallArea: "X001", Genre: "G001"}
+	ambiguities := []entity.Ambiguity{{Field: "location", Term: "中央"}, {Field: "genre", Term: "バル"}}
+
+	tests := []struct {
+		name       string
+		req        SearchRequest
+		prompt     string
+		conditions fakeConditions
+		origin     string
+		lat, lng   float64
+		radius     int
+		// keepsAreas はエリアの指定とエリアの曖昧さが残ることを示します
+		keepsAreas bool
+		unknown    []string
+	}{
+		{
+			name:   "集合駅の中間地点",
+			req:    SearchRequest{MeetStations: []string{"新宿", "渋谷駅"}},
+			origin: "新宿駅・渋谷駅の中間地点（代々木駅付近）",
+			lat:    midLat, lng: midLng, radius: entity.DefaultRadiusMeters,
+		},
+		{
+			name:    "知らない集合駅",
+			req:     SearchRequest{MeetStations: []string{"新宿", "どこか"}},
+			unknown: []string{"どこか"}, keepsAreas: true,
+		},
+		{
+			name:       "クエリ中の中間地点の表現",
+			prompt:     "新宿と渋谷の間で飲みたい",
+			conditions: fakeConditions{midpoint: true, rangeCode: 2},
+			origin:     "新宿駅・渋谷駅の中間地点（代々木駅付近）",
+			lat:        midLat, lng: midLng, radius: 500,
+		},
+		{
+			name:   "クエリ中の駅",
+			prompt: "渋谷駅の近くの居酒屋",
+			origin: "渋谷駅",
+			lat:    shibuya.Lat, lng: shibuya.Lng, radius: entity.DefaultRadiusMeters,
+		},
+		{
+			name:       "駅を付けない駅名は使わない",
+			prompt:     "渋谷の居酒屋",
+			keepsAreas: true,
+		},
+		{
+			name:       "現在地",
+			req:        SearchRequest{Lat: 35.7, Lng: 139.7},
+			conditions: fakeConditions{rangeCode: 1},
+			origin:     "現在地",
+			lat:        35.7, lng: 139.7, radius: 300, keepsAreas: true,
+		},
+		{
+			name:   "駅は現在地より優先",
+			req:    SearchRequest{Lat: 35.7, Lng: 139.7},
+			prompt: "新宿駅で",
+			origin: "新宿駅",
+			lat:    shinjuku.Lat, lng: shinjuku.Lng, radius: entity.DefaultRadiusMeters,
+		},
+		{
+			name:       "現在地がなければ距離の表現を使わない",
+			prompt:     "近くの居酒屋",
+			conditions: fakeConditions{rangeCode: 1},
+			keepsAreas: true,
+		},
+	}
+	for _, tt := range tests {
+		u := &GetRestaurantUsecase{stations: stations, conditions: tt.conditions}
+		params := areas
+		origin, remaining, unknown := u.applyLocation(&params, tt.req, tt.prompt, ambiguities, &entity.MappingTrace{})
+
+		if origin != tt.origin || params.Lat != tt.lat || params.Lng != tt.lng || params.RadiusMeters != tt.radius {
+			t.Errorf("%s: origin = %q (%g, %g) %dm, want %q (%g, %g) %dm", tt.name,
+				origin, params.Lat, params.Lng, params.RadiusMeters, tt.origin, tt.lat, tt.lng, tt.radius)
+		}
+		if !reflect.DeepEqual(unknown, tt.unknown) {
+			t.Errorf("%s: unknownStations = %v, want %v", tt.name, unknown, tt.unknown)
+		}
+		if params.Genre != "G001" {
+			t.Errorf("%s: ジャンルが変わりました: %q", tt.name, params.Genre)
+		}
+		wantAreas, wantAmbiguities := entity.SearchCriteria{}, ambiguities[1:]
+		if tt.keepsAreas {
+			wantAreas, wantAmbiguities = areas, ambiguities
+		}
+		if params.LargeArea != wantAreas.LargeArea || params.MiddleArea != wantAreas.MiddleArea || params.SmallArea != wantAreas.SmallArea {
+			t.Errorf("%s: areas = (%q, %q, %q), want (%q, %q, %q)", tt.name, params.LargeArea, params.MiddleArea, params.SmallArea,
+				wantAreas.LargeArea, wantAreas.MiddleArea, wantAreas.SmallArea)
+		}
+		if !reflect.DeepEqual(remaining, wantAmbiguities) {
+			t.Errorf("%s: ambiguities = %+v, want %+v", tt.name, remaining, wantAmbiguities)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package usecase
+
+import (
+	"restaurant-finder/Domain/entity"
+	"restaurant-finder/Domain/repository"
+)
+
+// SynonymUsecase 同義語辞書を閲覧・編集するユースケース
+type SynonymUsecase struct {
+	store repository.SynonymRepository
+}
+
+// NewSynonymUsecase SynonymUsecaseのコンストラクタ
+func NewSynonymUsecase(store repository.SynonymRepository) *SynonymUsecase {
+	return &SynonymUsecase{store: store}
+}
+
+// ListSynonyms 登録されている同義語の一覧を返す
+func (u *SynonymUsecase) ListSynonyms() []entity.Synonym {
+	return u.store.List()
+}
+
+// SaveSynonym 同義語を登録する（同じ別名があれば置き換える）
+func (u *SynonymUsecase) SaveSynonym(syn entity.Synonym) error {
+	return u.store.Add(syn)
+}
+
+// DeleteSynonym 同義語を削除する
+func (u *SynonymUsecase) DeleteSynonym(alias string) error {
+	return u.store.Remove(alias)
+}
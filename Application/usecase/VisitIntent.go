@@ -0,0 +1,21 @@
+package usecase
+
+import "restaurant-finder/Domain/entity"
+
+// applyVisitIntent は読み取った来店の条件を検索条件に反映します
+func applyVisitIntent(params *entity.SearchCriteria, intent entity.VisitIntent) {
+	switch intent.Meal {
+	case entity.MealLunch:
+		params.SetFacility(entity.FacilityLunch, true)
+	case entity.MealLateNight:
+		params.SetFacility(entity.FacilityMidnight, true)
+	}
+	if intent.At != nil {
+		if hour := intent.At.In(entity.JST).Hour(); hour >= 23 || hour < 5 {
+			params.SetFacility(entity.FacilityMidnight, true)
+		}
+	}
+	if intent.PartySize > 0 && params.PartyCapacity == 0 {
+		params.PartyCapacity = intent.PartySize
+	}
+}
@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"restaurant-finder/Domain/entity"
+)
+
+func TestApplyVisitIntent(t *testing.T) {
+	lateNight := time.Date(2025, 6, 5, 0, 30, 0, 0, entity.JST)
+	lateEvening := time.Date(2025, 6, 4, 23, 0, 0, 0, entity.JST)
+	earlyMorning := time.Date(2025, 6, 5, 5, 0, 0, 0, entity.JST)
+	evening := time.Date(2025, 6, 4, 19, 0, 0, 0, entity.JST)
+	// UTC の 15:30 は日本時間の 0:30
+	lateNightUTC := time.Date(2025, 6, 4, 15, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		params entity.SearchCriteria
+		intent entity.VisitIntent
+		want   entity.SearchCriteria
+	}{
+		{"ランチ", entity.SearchCriteria{}, entity.VisitIntent{Meal: entity.MealLunch}, entity.SearchCriteria{Facilities: []string{entity.FacilityLunch}}},
+		{"深夜", entity.SearchCriteria{}, entity.VisitIntent{Meal: entity.MealLateNight}, entity.SearchCriteria{Facilities: []string{entity.FacilityMidnight}}},
+		{"深夜の時刻", entity.SearchCriteria{}, entity.VisitIntent{At: &lateNight}, entity.SearchCriteria{Facilities: []string{entity.FacilityMidnight}}},
+		{"23時は深夜", entity.SearchCriteria{}, entity.VisitIntent{At: &lateEvening}, entity.SearchCriteria{Facilities: []string{entity.FacilityMidnight}}},
+		{"5時は深夜ではない", entity.SearchCriteria{}, entity.VisitIntent{At: &earlyMorning}, entity.SearchCriteria{}},
+		{"夜の時刻は条件を変えない", entity.SearchCriteria{}, entity.VisitIntent{Meal: entity.MealDinner, At: &evening}, entity.SearchCriteria{}},
+		{"日本時間で判定する", entity.SearchCriteria{}, entity.VisitIntent{At: &lateNightUTC}, entity.SearchCriteria{Facilities: []string{entity.FacilityMidnight}}},
+		{"深夜の食事と時刻で重複しない", entity.SearchCriteria{}, entity.VisitIntent{Meal: entity.MealLateNight, At: &lateNight}, entity.SearchCriteria{Facilities: []string{entity.FacilityMidnight}}},
+		{"指定済みの条件に追加", entity.SearchCriteria{Facilities: []string{entity.FacilityPrivateRoom}}, entity.VisitIntent{Meal: entity.MealLunch},
+			entity.SearchCriteria{Facilities: []string{entity.FacilityPrivateRoom, entity.FacilityLunch}}},
+		{"人数", entity.SearchCriteria{}, entity.VisitIntent{PartySize: 6}, entity.SearchCriteria{PartyCapacity: 6}},
+		{"指定済みの人数は変えない", entity.SearchCriteria{PartyCapacity: 10}, entity.VisitIntent{PartySize: 6}, entity.SearchCriteria{PartyCapacity: 10}},
+	}
+	for _, tt := range tests {
+		params := tt.params
+		applyVisitIntent(&params, tt.intent)
+		if !reflect.DeepEqual(params, tt.want) {
+			t.Errorf("%s: params = %+v, want %+v", tt.name, params, tt.want)
+		}
+	}
+}
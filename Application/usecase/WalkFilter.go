@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"sort"
+
+	"restaurant-finder/Domain/entity"
+)
+
+// filterByWalk は駅からの徒歩の時間が上限を超える店舗を除きます
+// 徒歩の時間を読み取れなかった店舗は除外せず、そのまま残します
+func filterByWalk(shops []entity.Restaurant, maxMinutes int) (kept []entity.Restaurant, excluded int) {
+	kept = make([]entity.Restaurant, 0, len(shops))
+	for _, shop := range shops {
+		if shop.AccessInfo.Parsed && shop.AccessInfo.WalkMinutes > maxMinutes {
+			excluded++
+			continue
+		}
+		kept = append(kept, shop)
+	}
+	return kept, excluded
+}
+
+// sortByWalk は駅からの徒歩の時間が短い順に店舗を並べます（読み取れなかった店舗は最後に並べます）
+func sortByWalk(shops []entity.Restaurant) []entity.Restaurant {
+	sorted := append([]entity.Restaurant(nil), shops...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ai, aj := sorted[i].AccessInfo, sorted[j].AccessInfo
+		if ai.Parsed != aj.Parsed {
+			return ai.Parsed
+		}
+		return ai.WalkMinutes < aj.WalkMinutes
+	})
+	return sorted
+}
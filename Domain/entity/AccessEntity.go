@@ -0,0 +1,12 @@
+package entity
+
+// AccessInfo は店舗のアクセス（"JR渋谷駅ハチ公口より徒歩3分" など）から読み取った最寄り駅です
+// アクセスに複数の駅が書かれている場合は、徒歩の時間が最も短いものです
+type AccessInfo struct {
+	Station string `json:"station,omitempty"`
+	Exit    string `json:"exit,omitempty"`
+	// WalkMinutes は駅からの徒歩の分数です（駅直結は 0）
+	WalkMinutes int `json:"walk_minutes"`
+	// Parsed は徒歩の時間を読み取れたかを示します（false の場合 WalkMinutes は不明）
+	Parsed bool `json:"parsed"`
+}
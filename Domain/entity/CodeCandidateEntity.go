@@ -0,0 +1,23 @@
+package entity
+
+// CodeCandidate は名前から HotPepper のコードを解決する際の候補です
+type CodeCandidate struct {
+	// Category は "large_area" / "middle_area" / "small_area" / "genre" / "budget" のいずれかです
+	Category string  `json:"category"`
+	Code     string  `json:"code"`
+	Name     string  `json:"name"`
+	Score    float64 `json:"score"`
+	// 上位のエリア（小区分なら中区分と大区分、中区分なら大区分）
+	MiddleAreaCode string `json:"middle_area_code,omitempty"`
+	MiddleAreaName string `json:"middle_area_name,omitempty"`
+	LargeAreaCode  string `json:"large_area_code,omitempty"`
+	LargeAreaName  string `json:"large_area_name,omitempty"`
+}
+
+// Ambiguity は 1 つの語に対して同程度のスコアの候補が複数あったことを表します
+type Ambiguity struct {
+	// Field は抽出項目名（"location" / "small_area" / "genre" など）です
+	Field      string          `json:"field"`
+	Term       string          `json:"term"`
+	Candidates []CodeCandidate `json:"candidates"`
+}
@@ -0,0 +1,18 @@
+package entity
+
+// FacetValue はファセットの 1 つの値と、その値に該当する店舗数です
+type FacetValue struct {
+	// Value は絞り込みに使う値（ジャンル・予算のコード、または条件名）です
+	Value    string `json:"value"`
+	Label    string `json:"label"`
+	Count    int    `json:"count"`
+	Selected bool   `json:"selected"`
+}
+
+// Facet は検索結果を絞り込むための項目（ジャンル・予算・こだわり条件）です
+type Facet struct {
+	// Field は絞り込みに使う検索条件（"genre" / "budget" / "flags"）です
+	Field  string       `json:"field"`
+	Label  string       `json:"label"`
+	Values []FacetValue `json:"values"`
+}
@@ -0,0 +1,182 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// JST は日本標準時です（営業時間と祝日の判定に使用）
+var JST = time.FixedZone("Asia/Tokyo", 9*60*60)
+
+// fixedHolidays は毎年同じ日付の祝日です（月, 日, 名称, 開始年, 終了年）
+var fixedHolidays = []struct {
+	month      time.Month
+	day        int
+	name       string
+	start, end int
+}{
+	{time.January, 1, "元日", 1949, 0},
+	{time.February, 11, "建国記念の日", 1967, 0},
+	{time.February, 23, "天皇誕生日", 2020, 0},
+	{time.April, 29, "昭和の日", 1989, 0},
+	{time.May, 3, "憲法記念日", 1949, 0},
+	{time.May, 4, "みどりの日", 2007, 0},
+	{time.May, 5, "こどもの日", 1949, 0},
+	{time.July, 20, "海の日", 1996, 2002},
+	{time.August, 11, "山の日", 2016, 0},
+	{time.September, 15, "敬老の日", 1966, 2002},
+	{time.October, 10, "体育の日", 1966, 1999},
+	{time.November, 3, "文化の日", 1948, 0},
+	{time.November, 23, "勤労感謝の日", 1948, 0},
+	{time.December, 23, "天皇誕生日", 1989, 2018},
+}
+
+// mondayHolidays は第 n 月曜日の祝日（ハッピーマンデー）です
+var mondayHolidays = []struct {
+	month      time.Month
+	week       int
+	name       string
+	start, end int
+}{
+	{time.January, 2, "成人の日", 2000, 0},
+	{time.July, 3, "海の日", 2003, 0},
+	{time.September, 3, "敬老の日", 2003, 0},
+	{time.October, 2, "体育の日", 2000, 2019},
+	{time.October, 2, "スポーツの日", 2020, 0},
+}
+
+// specialHolidays は特別法で定められた祝日と、移動した祝日です
+// 値が空の日付は、その年は通常の規則による祝日ではないことを示します
+var specialHolidays = map[string]string{
+	"2019-04-30": "国民の休日",
+	"2019-05-01": "即位の日",
+	"2019-05-02": "国民の休日",
+	"2019-10-22": "即位礼正殿の儀の行われる日",
+	// 東京オリンピック・パラリンピックに伴う移動
+	"2020-07-20": "",
+	"2020-07-23": "海の日",
+	"2020-07-24": "スポーツの日",
+	"2020-08-10": "山の日",
+	"2020-08-11": "",
+	"2020-10-12": "",
+	"2021-07-19": "",
+	"2021-07-22": "海の日",
+	"2021-07-23": "スポーツの日",
+	"2021-08-08": "山の日",
+	"2021-08-11": "",
+	"2021-10-11": "",
+}
+
+var (
+	holidayCache   = make(map[int]map[string]string)
+	holidayCacheMu sync.Mutex
+)
+
+// HolidayName は日付が祝日（振替休日・国民の休日を含む）であれば名称を返します
+func HolidayName(t time.Time) (string, bool) {
+	t = t.In(JST)
+	name, ok := holidaysOfYear(t.Year())[t.Format("2006-01-02")]
+	return name, ok
+}
+
+// IsHoliday は日付が祝日かどうかを返します
+func IsHoliday(t time.Time) bool {
+	_, ok := HolidayName(t)
+	return ok
+}
+
+// holidaysOfYear は 1 年分の祝日を計算して返します（キーは "2006-01-02" 形式の日付）
+func holidaysOfYear(year int) map[string]string {
+	holidayCacheMu.Lock()
+	defer holidayCacheMu.Unlock()
+	if holidays, ok := holidayCache[year]; ok {
+		return holidays
+	}
+
+	holidays := make(map[string]string)
+	date := func(month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, JST)
+	}
+	inEffect := func(start, end int) bool {
+		return year >= start && (end == 0 || year <= end)
+	}
+
+	for _, h := range fixedHolidays {
+		if inEffect(h.start, h.end) {
+			holidays[date(h.month, h.day).Format("2006-01-02")] = h.name
+		}
+	}
+	for _, h := range mondayHolidays {
+		if inEffect(h.start, h.end) {
+			holidays[nthMonday(year, h.month, h.week).Format("2006-01-02")] = h.name
+		}
+	}
+	if day := equinoxDay(year, 20.8431); day > 0 {
+		holidays[date(time.March, day).Format("2006-01-02")] = "春分の日"
+	}
+	if day := equinoxDay(year, 23.2488); day > 0 {
+		holidays[date(time.September, day).Format("2006-01-02")] = "秋分の日"
+	}
+	prefix := fmt.Sprintf("%04d-", year)
+	for key, name := range specialHolidays {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if name == "" {
+			delete(holidays, key)
+		} else {
+			holidays[key] = name
+		}
+	}
+
+	// 国民の休日: 前日と翌日が祝日である平日
+	for d := date(time.January, 2); d.Year() == year; d = d.AddDate(0, 0, 1) {
+		key := d.Format("2006-01-02")
+		if _, ok := holidays[key]; ok || d.Weekday() == time.Sunday {
+			continue
+		}
+		_, before := holidays[d.AddDate(0, 0, -1).Format("2006-01-02")]
+		_, after := holidays[d.AddDate(0, 0, 1).Format("2006-01-02")]
+		if before && after && year >= 1986 {
+			holidays[key] = "国民の休日"
+		}
+	}
+
+	// 振替休日: 祝日が日曜日の場合、その後の最初の祝日でない日
+	for key := range holidays {
+		d, _ := time.ParseInLocation("2006-01-02", key, JST)
+		if d.Weekday() != time.Sunday || year < 1973 {
+			continue
+		}
+		substitute := d.AddDate(0, 0, 1)
+		for {
+			if _, ok := holidays[substitute.Format("2006-01-02")]; !ok {
+				break
+			}
+			substitute = substitute.AddDate(0, 0, 1)
+		}
+		if substitute.Year() == year {
+			holidays[substitute.Format("2006-01-02")] = "振替休日"
+		}
+	}
+
+	holidayCache[year] = holidays
+	return holidays
+}
+
+// nthMonday は指定した月の第 n 月曜日を返します
+func nthMonday(year int, month time.Month, n int) time.Time {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, JST)
+	offset := (int(time.Monday) - int(first.Weekday()) + 7) % 7
+	return first.AddDate(0, 0, offset+7*(n-1))
+}
+
+// equinoxDay は春分日・秋分日の日付を近似式で計算します（1980〜2099 年のみ。範囲外は 0）
+func equinoxDay(year int, base float64) int {
+	if year < 1980 || year > 2099 {
+		return 0
+	}
+	return int(base + 0.242194*float64(year-1980) - float64((year-1980)/4))
+}
@@ -0,0 +1,56 @@
+package entity
+
+import (
+	"strings"
+	"unicode"
+)
+
+// 対応している言語コード
+const (
+	LanguageJapanese = "ja"
+	LanguageEnglish  = "en"
+	LanguageChinese  = "zh"
+	LanguageKorean   = "ko"
+)
+
+// chineseMarkers は漢字のみの文を中国語と判定するための語です
+// 日本語でも使う字（"面"・"間"・"的"・"附近" など）は含めず、簡体字にしかない字と中国語特有の語だけにします
+var chineseMarkers = []string{
+	"餐厅", "餐馆", "好吃", "推荐", "想吃", "哪里", "这", "吗", "们", "个", "吃", "饭",
+	"东京", "涩谷", "车站", "烤肉", "火锅", "拉面",
+}
+
+// DetectLanguage はクエリの言語を文字種から推定します
+func DetectLanguage(text string) string {
+	var kana, hangul, han, latin int
+	for _, r := range text {
+		switch {
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+			kana++
+		case unicode.Is(unicode.Hangul, r):
+			hangul++
+		case unicode.Is(unicode.Han, r):
+			han++
+		case r < unicode.MaxASCII && unicode.IsLetter(r):
+			latin++
+		}
+	}
+
+	switch {
+	case hangul > 0 && hangul >= kana:
+		return LanguageKorean
+	case kana > 0:
+		return LanguageJapanese
+	case han > 0:
+		// 漢字のみの場合は日本語のクエリ（例: "渋谷 居酒屋"）もあるため、中国語特有の語があるときだけ中国語とする
+		for _, marker := range chineseMarkers {
+			if strings.Contains(text, marker) {
+				return LanguageChinese
+			}
+		}
+		return LanguageJapanese
+	case latin > 0:
+		return LanguageEnglish
+	}
+	return LanguageJapanese
+}
@@ -0,0 +1,54 @@
+package entity
+
+import "math"
+
+const (
+	// earthRadiusMeters は地球の半径（メートル）です
+	earthRadiusMeters = 6371000.0
+	// DefaultRange は距離の指定がない場合の検索範囲（1000m）です
+	DefaultRange = 3
+	// DefaultRadiusMeters は距離の指定がない場合の検索範囲の半径（メートル）です
+	DefaultRadiusMeters = 1000
+)
+
+// rangeMeters は range コード（画面の距離の選択肢と HotPepper API の range）と半径（メートル）の対応です
+var rangeMeters = map[int]int{
+	1: 300,
+	2: 500,
+	3: 1000,
+	4: 2000,
+	5: 3000,
+}
+
+// RangeMeters は range コードの半径（メートル）を返します
+func RangeMeters(code int) int {
+	return rangeMeters[code]
+}
+
+// RangeForMeters は指定した距離を含む最小の range コードを返します（3000m を超える場合は 5）
+func RangeForMeters(meters int) int {
+	for code := 1; code <= 5; code++ {
+		if rangeMeters[code] >= meters {
+			return code
+		}
+	}
+	return 5
+}
+
+// HaversineMeters は 2 点間の大円距離（メートル）を返します
+func HaversineMeters(lat1, lng1, lat2, lng2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat := toRad(lat2 - lat1)
+	dLng := toRad(lng2 - lng1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// ValidLatLng は緯度・経度が有効な範囲にあるかを返します（0, 0 は未指定とみなします）
+func ValidLatLng(lat, lng float64) bool {
+	if lat == 0 && lng == 0 {
+		return false
+	}
+	return lat >= -90 && lat <= 90 && lng >= -180 && lng <= 180
+}
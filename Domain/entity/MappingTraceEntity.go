@@ -0,0 +1,47 @@
+package entity
+
+import "fmt"
+
+// TraceStep はマッピングの 1 段階の記録です
+type TraceStep struct {
+	Stage  string `json:"stage"`
+	Detail string `json:"detail"`
+}
+
+// MappingTrace は自然文クエリが検索パラメータに変換されるまでの過程の記録です（explain モード用）
+type MappingTrace struct {
+	// Model は抽出に使ったモデル名です
+	Model string `json:"model,omitempty"`
+	// RawLLMOutput は LLM が返したテキストそのものです
+	RawLLMOutput string `json:"raw_llm_output,omitempty"`
+	// Extracted は LLM の出力から取り出した項目です
+	Extracted map[string]string `json:"extracted,omitempty"`
+	// AppliedSynonyms は適用した同義語です
+	AppliedSynonyms []Synonym `json:"applied_synonyms,omitempty"`
+	// Candidates は項目ごとにマッチングで検討した候補とスコアです
+	Candidates map[string][]CodeCandidate `json:"candidates,omitempty"`
+	// ChosenCodes は採用したコードです
+	ChosenCodes map[string]string `json:"chosen_codes,omitempty"`
+	// QueryString は提供元に送るリクエスト（HotPepper API の URL など）です（API キーは伏せ字）
+	QueryString string `json:"query_string,omitempty"`
+	// Steps は処理の段階ごとの記録です
+	Steps []TraceStep `json:"steps"`
+}
+
+// AddStep は段階の記録を追加します。trace が nil の場合は何もしません
+func (t *MappingTrace) AddStep(stage, format string, args ...interface{}) {
+	if t == nil {
+		return
+	}
+	t.Steps = append(t.Steps, TraceStep{Stage: stage, Detail: fmt.Sprintf(format, args...)})
+}
+
+// Clone は記録のコピーを返します（段階の追加が元の記録に影響しないようにします）
+func (t *MappingTrace) Clone() *MappingTrace {
+	if t == nil {
+		return nil
+	}
+	copied := *t
+	copied.Steps = append([]TraceStep(nil), t.Steps...)
+	return &copied
+}
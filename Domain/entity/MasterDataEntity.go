@@ -0,0 +1,96 @@
+package entity
+
+// MasterData は format.json から読み込んだエリア・ジャンル・予算の選択肢です
+type MasterData struct {
+	LargeAreas  []MasterOption
+	MiddleAreas []MasterOption
+	SmallAreas  []MasterOption
+	Genres      []MasterOption
+	Budgets     []MasterOption
+}
+
+// MiddleAreasIn は大区分に属する中区分を返します
+func (m *MasterData) MiddleAreasIn(largeArea string) []MasterOption {
+	return optionsWithParent(m.MiddleAreas, largeArea)
+}
+
+// SmallAreasIn は中区分に属する小区分を返します
+func (m *MasterData) SmallAreasIn(middleArea string) []MasterOption {
+	return optionsWithParent(m.SmallAreas, middleArea)
+}
+
+// ReconcileAreas は上位のエリアと整合しない下位のエリアを取り除きます
+// 大区分だけを変更した場合などに、別の大区分の中区分・小区分で検索しないようにします
+func (m *MasterData) ReconcileAreas(params *SearchCriteria) {
+	if params.LargeArea != "" && params.MiddleArea != "" {
+		if parent := findOption(m.MiddleAreas, params.MiddleArea).ParentCode; parent != "" && parent != params.LargeArea {
+			params.MiddleArea = ""
+		}
+	}
+	if params.MiddleArea != "" && params.SmallArea != "" {
+		if parent := findOption(m.SmallAreas, params.SmallArea).ParentCode; parent != "" && parent != params.MiddleArea {
+			params.SmallArea = ""
+		}
+	}
+	if params.MiddleArea == "" && params.SmallArea != "" && params.LargeArea != "" {
+		// 小区分だけが残った場合は、その中区分が指定した大区分に属するか確認する
+		middle := findOption(m.SmallAreas, params.SmallArea).ParentCode
+		if parent := findOption(m.MiddleAreas, middle).ParentCode; parent != "" && parent != params.LargeArea {
+			params.SmallArea = ""
+		}
+	}
+}
+
+// Name は区分とコードから名称を返します。見つからない場合はコードを返します
+func (m *MasterData) Name(category, code string) string {
+	if option := findOption(m.options(category), code); option.Name != "" {
+		return option.Name
+	}
+	return code
+}
+
+// ParentCode はエリアの上位のコード（中区分なら大区分、小区分なら中区分）を返します
+func (m *MasterData) ParentCode(category, code string) string {
+	return findOption(m.options(category), code).ParentCode
+}
+
+// options は区分の選択肢を返します
+func (m *MasterData) options(category string) []MasterOption {
+	switch category {
+	case "large_area":
+		return m.LargeAreas
+	case "middle_area":
+		return m.MiddleAreas
+	case "small_area":
+		return m.SmallAreas
+	case "genre":
+		return m.Genres
+	case "budget":
+		return m.Budgets
+	}
+	return nil
+}
+
+// optionsWithParent は上位のコードが一致する選択肢を返します
+func optionsWithParent(options []MasterOption, parent string) []MasterOption {
+	if parent == "" {
+		return nil
+	}
+	matched := make([]MasterOption, 0)
+	for _, option := range options {
+		if option.ParentCode == parent {
+			matched = append(matched, option)
+		}
+	}
+	return matched
+}
+
+// findOption はコードに一致する選択肢を返します
+func findOption(options []MasterOption, code string) MasterOption {
+	for _, option := range options {
+		if option.Code == code {
+			return option
+		}
+	}
+	return MasterOption{}
+}
@@ -0,0 +1,9 @@
+package entity
+
+// MasterOption はマスタデータ（エリア・ジャンル・予算）の選択肢です
+type MasterOption struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+	// ParentCode は上位のエリアのコードです（中区分なら大区分、小区分なら中区分）
+	ParentCode string `json:"parent_code,omitempty"`
+}
@@ -0,0 +1,9 @@
+package entity
+
+// NaturalLanguageSummary は検索結果の自然言語による説明です
+type NaturalLanguageSummary struct {
+	Text  string
+	Usage TokenUsage
+	// Tier は説明を生成したモデル名です
+	Tier string
+}
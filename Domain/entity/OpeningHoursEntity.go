@@ -0,0 +1,71 @@
+package entity
+
+import "time"
+
+// MinutesPerDay は 1 日の分数です
+const MinutesPerDay = 24 * 60
+
+// TimeRange は営業時間帯です。0:00 からの分で表し、日付をまたぐ場合は End が 1440 を超えます
+type TimeRange struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// OpeningHours は営業時間・定休日の文字列を解析した週単位の営業予定です
+type OpeningHours struct {
+	// Weekly は曜日ごとの営業時間帯です（添字は time.Weekday、0 が日曜日）
+	Weekly [7][]TimeRange `json:"weekly"`
+	// Holiday / HolidayEve は祝日・祝前日の営業時間帯です（指定がない場合は曜日の営業時間帯を使います）
+	Holiday       []TimeRange `json:"holiday,omitempty"`
+	HasHoliday    bool        `json:"has_holiday"`
+	HolidayEve    []TimeRange `json:"holiday_eve,omitempty"`
+	HasHolidayEve bool        `json:"has_holiday_eve"`
+	// ClosedWeekdays は定休日の曜日です
+	ClosedWeekdays [7]bool `json:"closed_weekdays"`
+	// ClosedOnHolidays は祝日が定休日であることを示します
+	ClosedOnHolidays bool `json:"closed_on_holidays"`
+	// Irregular は不定休など、定休日以外にも休みがあることを示します
+	Irregular bool `json:"irregular"`
+	// Parsed は営業時間帯を 1 つ以上読み取れたことを示します
+	Parsed bool `json:"parsed"`
+}
+
+// IsOpenAt は指定した日時に営業しているかを返します
+// 営業時間を解析できなかった場合は known が false になります
+func (hours *OpeningHours) IsOpenAt(at time.Time) (open bool, known bool) {
+	if hours == nil || !hours.Parsed {
+		return false, false
+	}
+	at = at.In(JST)
+	minute := at.Hour()*60 + at.Minute()
+
+	// 当日の営業時間帯
+	for _, r := range hours.rangesOn(at) {
+		if r.Start <= minute && minute < r.End {
+			return true, true
+		}
+	}
+	// 前日から日付をまたいで続く営業時間帯
+	for _, r := range hours.rangesOn(at.AddDate(0, 0, -1)) {
+		if r.End > MinutesPerDay && r.Start <= minute+MinutesPerDay && minute+MinutesPerDay < r.End {
+			return true, true
+		}
+	}
+	return false, true
+}
+
+// rangesOn はその日の営業時間帯を返します（祝日・祝前日・定休日を考慮します）
+func (hours *OpeningHours) rangesOn(day time.Time) []TimeRange {
+	holiday := IsHoliday(day)
+	switch {
+	case holiday && hours.ClosedOnHolidays:
+		return nil
+	case holiday && hours.HasHoliday:
+		return hours.Holiday
+	case !holiday && hours.ClosedWeekdays[day.Weekday()]:
+		return nil
+	case IsHoliday(day.AddDate(0, 0, 1)) && hours.HasHolidayEve:
+		return hours.HolidayEve
+	}
+	return hours.Weekly[day.Weekday()]
+}
@@ -0,0 +1,29 @@
+package entity
+
+// 解釈結果を生成した段階（Tier）のうち、モデル名以外のもの
+const (
+	// InterpretationTierCache はキャッシュから解決したことを示します
+	InterpretationTierCache = "cache"
+	// InterpretationTierKeyword は LLM を使わずキーワード検索にフォールバックしたことを示します
+	InterpretationTierKeyword = "keyword"
+	// InterpretationTierParams はユーザーが指定した検索条件をそのまま使ったことを示します
+	InterpretationTierParams = "params"
+)
+
+// QueryInterpretation は自然文クエリを解釈した結果です
+type QueryInterpretation struct {
+	Params *SearchCriteria
+	Usage  TokenUsage
+	// CacheHit はキャッシュから解決結果を取得したことを示します
+	CacheHit bool
+	// Tier は解釈結果を生成したモデル名、または InterpretationTier* の値です
+	Tier string
+	// Language はクエリの言語（"ja" / "en" / "zh" / "ko"）です
+	Language string
+	// Candidates は抽出項目ごとのスコア付きのコード候補です
+	Candidates map[string][]CodeCandidate
+	// Ambiguities は候補が一意に決まらなかった項目です（最上位の候補のコードで検索します）
+	Ambiguities []Ambiguity
+	// Trace は解釈の過程の記録です
+	Trace *MappingTrace
+}
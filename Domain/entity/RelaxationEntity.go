@@ -0,0 +1,9 @@
+package entity
+
+// Relaxation は検索結果が 0 件だったために緩めた条件です
+type Relaxation struct {
+	// Field は緩めた条件（"location" / "flags" / "range" / "budget" / "small_area" / "middle_area"）です
+	Field string `json:"field"`
+	// Description はユーザーに表示する説明です
+	Description string `json:"description"`
+}
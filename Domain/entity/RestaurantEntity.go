@@ -0,0 +1,109 @@
+package entity
+
+import "slices"
+
+// SourceHotPepper は HotPepper グルメサーチ API から取得した店舗の提供元です
+const SourceHotPepper = "hotpepper"
+
+// 設備・サービスの名前です（検索条件のパラメータ名と同じ）
+const (
+	FacilityLunch       = "lunch"
+	FacilityPrivateRoom = "private_room"
+	FacilityFreeDrink   = "free_drink"
+	FacilityFreeFood    = "free_food"
+	FacilityMidnight    = "midnight"
+	FacilitySake        = "sake"
+	FacilityCocktail    = "cocktail"
+	FacilityWine        = "wine"
+	FacilityEnglish     = "english"
+)
+
+// FacilityOption は検索条件に指定できる設備・サービスとその表示名です
+type FacilityOption struct {
+	Name  string
+	Label string
+}
+
+// FacilityOptions は設備・サービスの一覧です
+// 画面のチップ、ファセット、結果 0 件時の緩和、共有 URL はこの一覧と順番を使います
+var FacilityOptions = []FacilityOption{
+	{Name: FacilityLunch, Label: "ランチあり"},
+	{Name: FacilityPrivateRoom, Label: "個室あり"},
+	{Name: FacilityFreeDrink, Label: "飲み放題"},
+	{Name: FacilityFreeFood, Label: "食べ放題"},
+	{Name: FacilityMidnight, Label: "23時以降も営業"},
+	{Name: FacilitySake, Label: "日本酒あり"},
+	{Name: FacilityCocktail, Label: "カクテルあり"},
+	{Name: FacilityWine, Label: "ワインあり"},
+	{Name: FacilityEnglish, Label: "英語メニューあり"},
+}
+
+// IsFacility は名前が FacilityOptions にある設備・サービスかを返します
+func IsFacility(name string) bool {
+	return slices.ContainsFunc(FacilityOptions, func(option FacilityOption) bool { return option.Name == name })
+}
+
+// Restaurant はデータの提供元に依存しないレストランです
+// 提供元のレスポンスは Infrastructure で変換してから使い、画面や検索の処理は提供元の形式に依存しません
+type Restaurant struct {
+	// ID はアプリ内で店舗を識別する ID です
+	// HotPepper の店舗は提供元の ID をそのまま使い、それ以外は "<提供元>:<提供元での ID>" です
+	ID string `json:"id"`
+	// Source はデータの提供元、SourceID は提供元での店舗の ID です
+	Source   string `json:"source"`
+	SourceID string `json:"source_id"`
+	Name     string `json:"name"`
+	// Catch は店舗の紹介文（キャッチコピー）です
+	Catch   string `json:"catch,omitempty"`
+	Address string `json:"address"`
+	// Lat / Lng は店舗の緯度・経度です（不明な場合は 0）
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+	// URL は店舗のページ、PhotoURL は店舗の写真です
+	URL      string `json:"url,omitempty"`
+	PhotoURL string `json:"photo_url,omitempty"`
+	// Access は交通アクセスの文章、AccessInfo はそこから読み取った最寄り駅・出口・徒歩の時間です
+	Access     string     `json:"access,omitempty"`
+	AccessInfo AccessInfo `json:"access_info"`
+	Genre      Category   `json:"genre"`
+	PriceRange PriceRange `json:"price_range"`
+	// OpenText / CloseText は営業時間・定休日の文章、Hours はそれを解析した営業予定です（解析できない場合は nil）
+	OpenText  string        `json:"open_text,omitempty"`
+	CloseText string        `json:"close_text,omitempty"`
+	Hours     *OpeningHours `json:"hours,omitempty"`
+	// Facilities はある設備・サービスの名前です（FacilityLunch など）
+	Facilities []string `json:"facilities,omitempty"`
+	// Capacity は総席数です（不明な場合は 0）
+	Capacity int `json:"capacity,omitempty"`
+	// Sources はこの店舗の情報を返したすべての提供元です（複数の提供元で同じ店舗が見つかった場合は 1 件にまとめます）
+	Sources []SourceRef `json:"sources,omitempty"`
+}
+
+// SourceRef は店舗の情報の提供元と、提供元での ID・ページです
+type SourceRef struct {
+	Source   string `json:"source"`
+	SourceID string `json:"source_id"`
+	// Label は画面に表示する提供元の名前です
+	Label string `json:"label"`
+	URL   string `json:"url,omitempty"`
+}
+
+// Category はジャンルなどの区分のコードと名称です
+type Category struct {
+	Code string `json:"code,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+// PriceRange は予算の区分と 1 人あたりの金額の範囲（円）です
+// Min / Max が 0 の場合は下限・上限がないことを示します
+type PriceRange struct {
+	Code string `json:"code,omitempty"`
+	Name string `json:"name,omitempty"`
+	Min  int    `json:"min,omitempty"`
+	Max  int    `json:"max,omitempty"`
+}
+
+// HasFacility は設備・サービスがあるかを返します
+func (r Restaurant) HasFacility(name string) bool {
+	return slices.Contains(r.Facilities, name)
+}
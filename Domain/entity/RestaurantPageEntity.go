@@ -0,0 +1,18 @@
+package entity
+
+// RestaurantPage はレストラン検索の 1 ページ分の結果と、ページ送りの情報です
+type RestaurantPage struct {
+	Restaurants []Restaurant `json:"restaurants"`
+	// Available は条件に一致する店舗の総数です
+	Available int `json:"available"`
+	// Start は Restaurants の先頭が何件目か（1 から）です
+	Start int `json:"start"`
+}
+
+// Returned はこのページの店舗数を返します
+func (p *RestaurantPage) Returned() int {
+	if p == nil {
+		return 0
+	}
+	return len(p.Restaurants)
+}
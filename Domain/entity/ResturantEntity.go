@@ -1,5 +1,11 @@
 package entity
 
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
 // HotPepperAPIのレスポンスのEntity
 // Shop構造体の定義
 type Shop struct {
@@ -9,7 +15,10 @@ type Shop struct {
 		PC string `json:"pc"`
 	} `json:"urls"`
 	Address string `json:"address"`
-	Photo   struct {
+	// Lat / Lng は店舗の緯度・経度です
+	Lat   float64 `json:"lat"`
+	Lng   float64 `json:"lng"`
+	Photo struct {
 		PC struct {
 			L string `json:"l"`
 			M string `json:"m"`
@@ -24,12 +33,49 @@ type Shop struct {
 	Open   string `json:"open"`
 	Close  string `json:"close"`
 	Genre  struct {
+		Code string `json:"code"`
 		Name string `json:"name"`
 	} `json:"genre"`
 	Catch  string `json:"catch"`
 	Budget struct {
+		Code string `json:"code"`
 		Name string `json:"name"`
 	} `json:"budget"`
+	// 設備・サービス（"あり" / "なし" などの文字列で返される）
+	PrivateRoom string `json:"private_room"`
+	FreeDrink   string `json:"free_drink"`
+	FreeFood    string `json:"free_food"`
+	Lunch       string `json:"lunch"`
+	Midnight    string `json:"midnight"`
+	Sake        string `json:"sake"`
+	Cocktail    string `json:"cocktail"`
+	Wine        string `json:"wine"`
+	English     string `json:"english"`
+	// Capacity は総席数です（不明な場合は 0）
+	Capacity FlexInt `json:"capacity"`
+}
+
+// FlexInt は数値または文字列（空文字を含む）で返される整数です
+type FlexInt int
+
+// UnmarshalJSON は 50 / "50" / "" のいずれの形式も受け付けます
+func (n *FlexInt) UnmarshalJSON(data []byte) error {
+	var i int
+	if err := json.Unmarshal(data, &i); err == nil {
+		*n = FlexInt(i)
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		*n = 0
+		return nil
+	}
+	i, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		i = 0
+	}
+	*n = FlexInt(i)
+	return nil
 }
 
 // HotPepperAPiのレスポンスの構造体
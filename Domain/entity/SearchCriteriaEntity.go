@@ -0,0 +1,50 @@
+package entity
+
+import "slices"
+
+// SearchCriteria は提供元に依存しない店舗の検索条件です
+// エリア・ジャンル・予算はマスターデータのコードで、各提供元のリクエストへの変換は提供元が行います
+type SearchCriteria struct {
+	// ID を指定した場合はその店舗だけを検索します（"<提供元>:<ID>" の形の場合はその提供元だけに問い合わせます）
+	ID      string `json:"id,omitempty"`
+	Keyword string `json:"keyword,omitempty"`
+	// Lat / Lng を指定した場合は、その位置から RadiusMeters 以内の店舗を検索します
+	Lat          float64 `json:"lat,omitempty"`
+	Lng          float64 `json:"lng,omitempty"`
+	RadiusMeters int     `json:"radius_meters,omitempty"`
+	LargeArea    string  `json:"large_area,omitempty"`
+	MiddleArea   string  `json:"middle_area,omitempty"`
+	SmallArea    string  `json:"small_area,omitempty"`
+	Genre        string  `json:"genre,omitempty"`
+	Budget       string  `json:"budget,omitempty"`
+	// Facilities は必須の設備・サービスの名前です（FacilityLunch など）
+	Facilities []string `json:"facilities,omitempty"`
+	// PartyCapacity を指定した場合は、この人数以上を収容できる店舗を検索します
+	PartyCapacity int `json:"party_capacity,omitempty"`
+	// Start（1 から）と Count で返すページを指定します
+	Start int `json:"start,omitempty"`
+	Count int `json:"count,omitempty"`
+}
+
+// RequiresFacility は設備・サービスが必須かを返します
+func (c *SearchCriteria) RequiresFacility(name string) bool {
+	return slices.Contains(c.Facilities, name)
+}
+
+// SetFacility は設備・サービスを必須にする、または必須の条件から外します
+func (c *SearchCriteria) SetFacility(name string, required bool) {
+	switch {
+	case required && !c.RequiresFacility(name):
+		// 元の配列を共有するコピーに影響しないよう、新しい配列に追加する
+		c.Facilities = append(slices.Clip(c.Facilities), name)
+	case !required:
+		c.Facilities = slices.DeleteFunc(slices.Clone(c.Facilities), func(f string) bool { return f == name })
+	}
+}
+
+// Clone は検索条件のコピーを返します（Facilities も複製し、コピーの変更が元に影響しないようにします）
+func (c *SearchCriteria) Clone() *SearchCriteria {
+	copied := *c
+	copied.Facilities = slices.Clone(c.Facilities)
+	return &copied
+}
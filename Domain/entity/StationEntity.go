@@ -0,0 +1,36 @@
+package entity
+
+import "math"
+
+// Station は駅の名前・読み・路線・位置です
+type Station struct {
+	Name string `json:"name"`
+	// Reading はひらがなの読み、Romaji は英語のクエリ用のローマ字表記です
+	Reading string `json:"reading"`
+	Romaji  string `json:"romaji"`
+	// Aliases は "なんば" のような別表記です
+	Aliases []string `json:"aliases,omitempty"`
+	Lines   []string `json:"lines"`
+	Lat     float64  `json:"lat"`
+	Lng     float64  `json:"lng"`
+}
+
+// Midpoint は駅の地理的な中間地点（球面上の重心）を返します
+func Midpoint(stations []Station) (lat, lng float64) {
+	if len(stations) == 0 {
+		return 0, 0
+	}
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	var x, y, z float64
+	for _, station := range stations {
+		la, ln := toRad(station.Lat), toRad(station.Lng)
+		x += math.Cos(la) * math.Cos(ln)
+		y += math.Cos(la) * math.Sin(ln)
+		z += math.Sin(la)
+	}
+	n := float64(len(stations))
+	x, y, z = x/n, y/n, z/n
+	lng = math.Atan2(y, x)
+	lat = math.Atan2(z, math.Sqrt(x*x+y*y))
+	return lat * 180 / math.Pi, lng * 180 / math.Pi
+}
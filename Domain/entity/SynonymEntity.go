@@ -0,0 +1,16 @@
+package entity
+
+// Synonym は利用者の言い回し（別名）を、エリア・ジャンルのコードやキーワードに対応付ける辞書の 1 件です
+type Synonym struct {
+	// Alias は利用者が入力する語です（例: "ハチ公前", "飲み屋"）
+	Alias string `json:"alias"`
+	// Field は対応付ける項目です
+	// "location" / "large_area" / "middle_area" / "small_area" / "genre" / "budget" / "keyword" のいずれかです
+	Field string `json:"field"`
+	// Code は対応する HotPepper のコードです（例: "G001"）
+	Code string `json:"code,omitempty"`
+	// Name はマスタデータの名称です。Code がない場合はこの名称でコードを解決します（例: "渋谷"）
+	Name string `json:"name,omitempty"`
+	// Keyword は検索キーワードに追加する語です（例: "焼き鳥"）
+	Keyword string `json:"keyword,omitempty"`
+}
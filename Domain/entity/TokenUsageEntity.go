@@ -0,0 +1,19 @@
+package entity
+
+// TokenUsage は LLM 呼び出しで消費したトークン数と推定コストを表します
+type TokenUsage struct {
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	TotalTokens      int     `json:"total_tokens"`
+	EstimatedCostUSD float64 `json:"estimated_cost_usd"`
+}
+
+// Add は別の TokenUsage を加算した結果を返します
+func (u TokenUsage) Add(other TokenUsage) TokenUsage {
+	return TokenUsage{
+		PromptTokens:     u.PromptTokens + other.PromptTokens,
+		CompletionTokens: u.CompletionTokens + other.CompletionTokens,
+		TotalTokens:      u.TotalTokens + other.TotalTokens,
+		EstimatedCostUSD: u.EstimatedCostUSD + other.EstimatedCostUSD,
+	}
+}
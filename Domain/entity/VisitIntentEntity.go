@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+// 食事の種類
+const (
+	MealLunch     = "lunch"
+	MealDinner    = "dinner"
+	MealDrinks    = "drinks"
+	MealLateNight = "late_night"
+)
+
+// VisitIntent はクエリから読み取った来店の日時・人数・食事の種類です
+type VisitIntent struct {
+	// At は来店予定の日時です（日付や時刻の指定がない場合は nil）
+	At *time.Time `json:"at,omitempty"`
+	// PartySize は人数です（指定がない場合は 0）
+	PartySize int `json:"party_size,omitempty"`
+	// Meal は食事の種類（Meal* のいずれか）です
+	Meal string `json:"meal,omitempty"`
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"time"
+
+	"restaurant-finder/Domain/entity"
+)
+
+// ConditionExtractor はクエリの文章から、検索条件の解釈とは別に来店の日時・距離・徒歩の上限などを読み取ります
+type ConditionExtractor interface {
+	// VisitIntent は来店の日時・人数・食事の種類を読み取ります。「今夜」などの相対的な日付は now から解決します
+	VisitIntent(text string, now time.Time) entity.VisitIntent
+	// Range は "徒歩5分以内" "近く" のような距離の表現を range コードに変換します。表現がない場合は 0 を返します
+	Range(text string) int
+	// WalkLimit は "駅から5分以内" "駅近" のような駅からの徒歩の上限（分）を返します。指定がない場合は 0 を返します
+	WalkLimit(text string) int
+	// WantsMidpoint は複数の駅の中間地点で探すことを求めているかを返します
+	WantsMidpoint(text string) bool
+}
@@ -0,0 +1,8 @@
+package repository
+
+import "restaurant-finder/Domain/entity"
+
+// MasterDataRepository はエリア・ジャンル・予算のマスタデータを読み込みます
+type MasterDataRepository interface {
+	MasterData() (*entity.MasterData, error)
+}
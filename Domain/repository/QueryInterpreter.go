@@ -0,0 +1,9 @@
+package repository
+
+import "restaurant-finder/Domain/entity"
+
+// QueryInterpreter は自然文のクエリを検索条件に変換します
+type QueryInterpreter interface {
+	// InterpretQuery はクエリを検索条件に変換します。useLLM が false の場合は LLM を使わずに辞書とキーワードだけで変換します
+	InterpretQuery(prompt string, useLLM bool) (*entity.QueryInterpretation, error)
+}
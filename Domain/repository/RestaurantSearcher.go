@@ -0,0 +1,15 @@
+package repository
+
+import "restaurant-finder/Domain/entity"
+
+// RestaurantSearcher は検索条件でレストランを検索し、指定したページの店舗を返します
+// ページは criteria の Start（1 から）と Count で指定します
+type RestaurantSearcher interface {
+	SearchRestaurants(criteria *entity.SearchCriteria) (*entity.RestaurantPage, error)
+}
+
+// RequestDescriber は検索条件を提供元に送るリクエストの形（API キーを伏せた URL など）で表します
+// explain モードの表示に使います。RestaurantSearcher が実装している場合だけ使います
+type RequestDescriber interface {
+	DescribeRequest(criteria *entity.SearchCriteria) string
+}
@@ -0,0 +1,9 @@
+package repository
+
+// RestaurantSource はレストランの提供元（HotPepper、チームのお気に入りの CSV など）です
+// 複数の提供元の結果はまとめて 1 つの RestaurantSearcher として検索に使います
+type RestaurantSource interface {
+	// Name は提供元の名前です（entity.Restaurant の Source と同じ）
+	Name() string
+	RestaurantSearcher
+}
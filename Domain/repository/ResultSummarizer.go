@@ -0,0 +1,8 @@
+package repository
+
+import "restaurant-finder/Domain/entity"
+
+// ResultSummarizer は検索結果をクエリに沿って自然文で説明します
+type ResultSummarizer interface {
+	Summarize(query string, restaurants []entity.Restaurant, criteria *entity.SearchCriteria) (*entity.NaturalLanguageSummary, error)
+}
@@ -0,0 +1,13 @@
+package repository
+
+import "restaurant-finder/Domain/entity"
+
+// StationRepository は駅名や座標から駅を探します
+type StationRepository interface {
+	// Find は駅名・読み・別表記が一致する駅を返します
+	Find(name string) (entity.Station, bool)
+	// Mentions は文章中に出てくる駅を返します。requireSuffix の場合は "駅" が付いたものだけを返します
+	Mentions(text string, requireSuffix bool) []entity.Station
+	// Nearest は座標に最も近い駅を返します
+	Nearest(lat, lng float64) (entity.Station, bool)
+}
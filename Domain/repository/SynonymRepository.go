@@ -0,0 +1,10 @@
+package repository
+
+import "restaurant-finder/Domain/entity"
+
+// SynonymRepository は同義語辞書を閲覧・編集します
+type SynonymRepository interface {
+	List() []entity.Synonym
+	Add(syn entity.Synonym) error
+	Remove(alias string) error
+}
@@ -0,0 +1,13 @@
+package repository
+
+import "restaurant-finder/Domain/entity"
+
+// UsageRepository は LLM の利用量を記録し、1 日の上限の範囲で LLM を呼べるかを判定します
+// 同時に呼び出しても上限を超えないよう、LLM を呼ぶ前に見積もった利用額を Reserve で確保し、
+// 呼び出した後に Record で実際の利用量に置き換えます
+type UsageRepository interface {
+	// Reserve は見積もった利用額を確保します。確保すると上限を超える場合は確保せずに false を返します
+	Reserve(estimatedCostUSD float64) bool
+	// Record は Reserve で確保した額を解放し、実際の利用量をクライアントごとに加算します
+	Record(clientID string, reservedCostUSD float64, usage entity.TokenUsage)
+}
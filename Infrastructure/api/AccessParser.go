@@ -0,0 +1,114 @@
+package api
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+	"restaurant-finder/Domain/entity"
+)
+
+var (
+	// accessSeparatorPattern はアクセスに複数の駅が書かれている場合の区切りです
+	accessSeparatorPattern = regexp.MustCompile(`[/、。\n]|\s{2,}`)
+	// accessStationPattern は "JR渋谷駅" "東京メトロ銀座線 銀座駅" のような駅名（路線名を含む）です
+	accessStationPattern = regexp.MustCompile(`([^\s/、。()（）「」]+?)駅`)
+	// accessExitPattern は駅名の直後の "ハチ公口" "A4出口" "東口" "南改札" のような出口です
+	accessExitPattern = regexp.MustCompile(`^\s*([a-z0-9一-龠ぁ-んァ-ヶー]{1,10}?(?:出口|改札口|改札|口))`)
+	// accessWalkPattern は "徒歩3分" "徒歩約5分" "歩いて2分" のような徒歩の時間です
+	accessWalkPattern = regexp.MustCompile(`(?:徒歩|歩いて)\s*(?:約|およそ)?\s*(\d{1,2})\s*分`)
+	// accessWalkSecondsPattern は "徒歩30秒" のような秒で書かれた徒歩の時間です
+	accessWalkSecondsPattern = regexp.MustCompile(`(?:徒歩|歩いて)\s*(?:約|およそ)?\s*(\d{1,3})\s*秒`)
+	// walkLimitPattern はクエリの "駅から5分以内" "駅徒歩3分" のような駅からの徒歩の上限です
+	walkLimitPattern = regexp.MustCompile(`駅\s*(?:から|より)?\s*(?:徒歩|歩いて)?\s*(\d{1,2})\s*分|(\d{1,2})\s*min(?:ute)?s?\s*(?:walk\s*)?from\s*(?:the\s*)?station`)
+)
+
+// stationLinePrefixes は駅名の前に付く事業者名です（路線名は "線" までを取り除きます）
+// "京急川崎" "京王八王子" のように私鉄の名前は駅名の一部になることが多いため、駅名に含まれない事業者名だけを取り除きます
+var stationLinePrefixes = []string{
+	"jr各線", "jr", "各線", "地下鉄", "東京メトロ", "大阪メトロ", "メトロ", "都営", "市営", "私鉄",
+}
+
+// directAccessWords は駅直結（徒歩 0 分）を表す語です
+var directAccessWords = []string{"駅直結", "駅構内", "駅ビル"}
+
+// defaultWalkLimit は "駅近" のように分数を指定しない場合の徒歩の上限です
+const defaultWalkLimit = 5
+
+// ParseAccess はアクセスの文章から最寄り駅・出口・徒歩の時間を読み取ります
+// 複数の駅が書かれている場合は、徒歩の時間が最も短いものを返します
+func ParseAccess(access string) entity.AccessInfo {
+	text := strings.ToLower(norm.NFKC.String(access))
+
+	best := entity.AccessInfo{}
+	for _, segment := range accessSeparatorPattern.Split(text, -1) {
+		info := parseAccessSegment(segment)
+		if info.Station == "" && !info.Parsed {
+			continue
+		}
+		switch {
+		case best.Station == "" && !best.Parsed:
+			best = info
+		case info.Parsed && (!best.Parsed || info.WalkMinutes < best.WalkMinutes):
+			best = info
+		}
+	}
+	return best
+}
+
+// parseAccessSegment は 1 つの駅についてのアクセスを読み取ります
+func parseAccessSegment(segment string) entity.AccessInfo {
+	info := entity.AccessInfo{}
+	if loc := accessStationPattern.FindStringSubmatchIndex(segment); loc != nil {
+		info.Station = stripLineName(segment[loc[2]:loc[3]])
+		if m := accessExitPattern.FindStringSubmatch(segment[loc[1]:]); m != nil {
+			info.Exit = strings.ToUpper(m[1])
+		}
+	}
+	if m := accessWalkPattern.FindStringSubmatch(segment); m != nil {
+		info.WalkMinutes, _ = strconv.Atoi(m[1])
+		info.Parsed = true
+	} else if m := accessWalkSecondsPattern.FindStringSubmatch(segment); m != nil {
+		// 秒で書かれている場合は分に切り上げます（"徒歩30秒" は 1 分）
+		seconds, _ := strconv.Atoi(m[1])
+		info.WalkMinutes = max((seconds+59)/60, 1)
+		info.Parsed = true
+	} else if containsAny(segment, directAccessWords...) {
+		info.Parsed = true
+	}
+	return info
+}
+
+// stripLineName は "東京メトロ銀座線銀座" のような文字列から路線名と事業者名を取り除き、駅名だけにします
+func stripLineName(name string) string {
+	if i := strings.LastIndex(name, "線"); i >= 0 && i+len("線") < len(name) {
+		// 路線名の後ろが駅名（"京王線京王八王子" の "京王" は駅名の一部なので残す）
+		return strings.TrimSpace(name[i+len("線"):])
+	}
+	for _, prefix := range stationLinePrefixes {
+		if rest := strings.TrimPrefix(name, prefix); rest != name && rest != "" {
+			name = rest
+			break
+		}
+	}
+	return strings.TrimSpace(name)
+}
+
+// ExtractWalkLimit はクエリの "駅から5分以内" "駅近" のような表現から駅からの徒歩の上限（分）を返します
+// 指定がない場合は 0 を返します
+func ExtractWalkLimit(text string) int {
+	text = strings.ToLower(norm.NFKC.String(text))
+	if m := walkLimitPattern.FindStringSubmatch(text); m != nil {
+		minutes := m[1]
+		if minutes == "" {
+			minutes = m[2]
+		}
+		n, _ := strconv.Atoi(minutes)
+		return n
+	}
+	if containsAny(text, "駅近", "駅チカ", "駅ちか", "駅前", "near the station", "near station") {
+		return defaultWalkLimit
+	}
+	return 0
+}
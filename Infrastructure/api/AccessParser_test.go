@@ -0,0 +1,52 @@
+package api
+
+import (
+	"testing"
+
+	"restaurant-finder/Domain/entity"
+)
+
+func TestParseAccess(t *testing.T) {
+	tests := []struct {
+		access string
+		want   entity.AccessInfo
+	}{
+		{"JR渋谷駅ハチ公口より徒歩3分", entity.AccessInfo{Station: "渋谷", Exit: "ハチ公口", WalkMinutes: 3, Parsed: true}},
+		{"東京メトロ銀座線 銀座駅A4出口から徒歩約2分", entity.AccessInfo{Station: "銀座", Exit: "A4出口", WalkMinutes: 2, Parsed: true}},
+		{"東京メトロ銀座線銀座駅 徒歩5分", entity.AccessInfo{Station: "銀座", WalkMinutes: 5, Parsed: true}},
+		{"京急川崎駅から徒歩4分", entity.AccessInfo{Station: "京急川崎", WalkMinutes: 4, Parsed: true}},
+		{"京王線京王八王子駅 徒歩1分", entity.AccessInfo{Station: "京王八王子", WalkMinutes: 1, Parsed: true}},
+		{"都営大江戸線 六本木駅 徒歩6分", entity.AccessInfo{Station: "六本木", WalkMinutes: 6, Parsed: true}},
+		{"都営新宿駅より徒歩2分", entity.AccessInfo{Station: "新宿", WalkMinutes: 2, Parsed: true}},
+		{"新宿駅東口から徒歩30秒", entity.AccessInfo{Station: "新宿", Exit: "東口", WalkMinutes: 1, Parsed: true}},
+		{"有楽町駅から徒歩90秒", entity.AccessInfo{Station: "有楽町", WalkMinutes: 2, Parsed: true}},
+		{"ＪＲ恵比寿駅 徒歩１０分／日比谷線恵比寿駅 徒歩７分", entity.AccessInfo{Station: "恵比寿", WalkMinutes: 7, Parsed: true}},
+		{"東京駅直結", entity.AccessInfo{Station: "東京", Parsed: true}},
+		{"池袋駅", entity.AccessInfo{Station: "池袋"}},
+		{"", entity.AccessInfo{}},
+	}
+	for _, tt := range tests {
+		if got := ParseAccess(tt.access); got != tt.want {
+			t.Errorf("ParseAccess(%q) = %+v, want %+v", tt.access, got, tt.want)
+		}
+	}
+}
+
+func TestExtractWalkLimit(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"渋谷駅から5分以内の居酒屋", 5},
+		{"新宿 駅徒歩3分", 3},
+		{"ramen 10 min walk from the station", 10},
+		{"駅近の焼肉", defaultWalkLimit},
+		{"near the station sushi", defaultWalkLimit},
+		{"渋谷 居酒屋", 0},
+	}
+	for _, tt := range tests {
+		if got := ExtractWalkLimit(tt.text); got != tt.want {
+			t.Errorf("ExtractWalkLimit(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
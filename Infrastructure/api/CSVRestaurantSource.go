@@ -0,0 +1,251 @@
+package api
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"restaurant-finder/Domain/entity"
+	"restaurant-finder/Domain/repository"
+)
+
+// defaultFavoritesFile はチームのお気に入りの店舗の既定のファイルです
+const defaultFavoritesFile = "favorites.csv"
+
+// お気に入りの店舗の提供元の名前と、画面に表示する名前です
+const (
+	favoritesSourceName  = "favorites"
+	favoritesSourceLabel = "チームのお気に入り"
+)
+
+// CSVRestaurantSource がレストランの提供元のポートを満たすことをコンパイル時に確認する
+var _ repository.RestaurantSource = (*CSVRestaurantSource)(nil)
+
+// CSVRestaurantSource は CSV ファイルに登録した店舗（チームのお気に入りなど）を検索する提供元です
+// 1 行目は列名で、name 以外の列は省略できます:
+// id, name, address, lat, lng, large_area, middle_area, small_area, genre_code, genre_name,
+// budget_code, budget_name, price_min, price_max, access, open, close, url, photo_url, facilities（";" 区切り）, capacity, note
+type CSVRestaurantSource struct {
+	name    string
+	label   string
+	entries []csvRestaurant
+}
+
+// csvRestaurant は CSV の 1 行の店舗と、検索条件との照合に使う項目です
+type csvRestaurant struct {
+	restaurant entity.Restaurant
+	largeArea  string
+	middleArea string
+	smallArea  string
+	// text はキーワードと照合する名前・ジャンル・住所・アクセス・メモを正規化した文字列です
+	text string
+}
+
+// NewCSVRestaurantSource は CSV ファイルを読み込んで CSVRestaurantSource を作成します。ファイルがない場合は店舗のない提供元になります
+func NewCSVRestaurantSource(name, label, path string) (*CSVRestaurantSource, error) {
+	source := &CSVRestaurantSource{name: name, label: label}
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return source, nil
+	}
+	if err != nil {
+		return source, fmt.Errorf("店舗の CSV を読み込めません: %w", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return source, fmt.Errorf("店舗の CSV の形式が不正です: %w", err)
+	}
+	if len(records) == 0 {
+		return source, nil
+	}
+
+	columns := make(map[string]int, len(records[0]))
+	for i, column := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")))] = i
+	}
+	if _, ok := columns["name"]; !ok {
+		return source, fmt.Errorf("店舗の CSV に name の列がありません: %s", path)
+	}
+	for i, record := range records[1:] {
+		value := func(column string) string {
+			if index, ok := columns[column]; ok && index < len(record) {
+				return strings.TrimSpace(record[index])
+			}
+			return ""
+		}
+		if value("name") == "" {
+			continue
+		}
+		source.entries = append(source.entries, source.newEntry(value, i+1))
+	}
+	fmt.Printf("店舗の CSV を読み込みました: %s (%d件)\n", path, len(source.entries))
+	return source, nil
+}
+
+// NewFavoritesSourceFromEnv は環境変数 FAVORITES_FILE のチームのお気に入りの CSV（既定は favorites.csv）を読み込みます
+// 読み込めない場合も警告を出して店舗のない提供元を返します
+func NewFavoritesSourceFromEnv() *CSVRestaurantSource {
+	path := os.Getenv("FAVORITES_FILE")
+	if path == "" {
+		path = defaultFavoritesFile
+	}
+	source, err := NewCSVRestaurantSource(favoritesSourceName, favoritesSourceLabel, path)
+	if err != nil {
+		fmt.Printf("警告: %v\n", err)
+	}
+	return source
+}
+
+// newEntry は CSV の 1 行から店舗を作成します。id の列がない場合は行番号を ID にします
+func (s *CSVRestaurantSource) newEntry(value func(column string) string, row int) csvRestaurant {
+	sourceID := value("id")
+	if sourceID == "" {
+		sourceID = strconv.Itoa(row)
+	}
+	lat, _ := strconv.ParseFloat(value("lat"), 64)
+	lng, _ := strconv.ParseFloat(value("lng"), 64)
+	if !entity.ValidLatLng(lat, lng) {
+		lat, lng = 0, 0
+	}
+	capacity, _ := strconv.Atoi(value("capacity"))
+
+	price := parsePriceRange(value("budget_code"), value("budget_name"))
+	if v, err := strconv.Atoi(value("price_min")); err == nil {
+		price.Min = v
+	}
+	if v, err := strconv.Atoi(value("price_max")); err == nil {
+		price.Max = v
+	}
+
+	facilities := make([]string, 0)
+	for _, facility := range strings.FieldsFunc(value("facilities"), func(r rune) bool { return r == ';' || r == '|' }) {
+		facility = strings.ToLower(strings.TrimSpace(facility))
+		if entity.IsFacility(facility) && !slices.Contains(facilities, facility) {
+			facilities = append(facilities, facility)
+		}
+	}
+
+	restaurant := entity.Restaurant{
+		ID:         s.name + ":" + sourceID,
+		Source:     s.name,
+		SourceID:   sourceID,
+		Name:       value("name"),
+		Catch:      value("note"),
+		Address:    value("address"),
+		Lat:        lat,
+		Lng:        lng,
+		URL:        value("url"),
+		PhotoURL:   value("photo_url"),
+		Access:     value("access"),
+		AccessInfo: ParseAccess(value("access")),
+		Genre:      entity.Category{Code: value("genre_code"), Name: value("genre_name")},
+		PriceRange: price,
+		OpenText:   value("open"),
+		CloseText:  value("close"),
+		Facilities: facilities,
+		Capacity:   capacity,
+		Sources: []entity.SourceRef{
+			{Source: s.name, SourceID: sourceID, Label: s.label, URL: value("url")},
+		},
+	}
+	if hours := ParseOpeningHours(restaurant.OpenText, restaurant.CloseText); hours != nil && hours.Parsed {
+		restaurant.Hours = hours
+	}
+
+	text := strings.Join([]string{
+		restaurant.Name, restaurant.Genre.Name, restaurant.Address, restaurant.Access,
+		restaurant.AccessInfo.Station, restaurant.Catch,
+	}, " ")
+	return csvRestaurant{
+		restaurant: restaurant,
+		largeArea:  value("large_area"),
+		middleArea: value("middle_area"),
+		smallArea:  value("small_area"),
+		text:       normalizeText(text),
+	}
+}
+
+// Name は提供元の名前を返します
+func (s *CSVRestaurantSource) Name() string {
+	return s.name
+}
+
+// Len は登録されている店舗の数を返します
+func (s *CSVRestaurantSource) Len() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.entries)
+}
+
+// SearchRestaurants は検索条件に一致する店舗から、Start / Count で指定したページを返します
+func (s *CSVRestaurantSource) SearchRestaurants(criteria *entity.SearchCriteria) (*entity.RestaurantPage, error) {
+	matched := make([]entity.Restaurant, 0)
+	if s != nil {
+		for _, entry := range s.entries {
+			if entry.matches(criteria) {
+				matched = append(matched, entry.restaurant)
+			}
+		}
+	}
+
+	count := criteria.Count
+	if count <= 0 {
+		count = 10
+	}
+	start := max(criteria.Start, 1)
+	offset := min(start-1, len(matched))
+	return &entity.RestaurantPage{
+		Restaurants: matched[offset:min(offset+count, len(matched))],
+		Available:   len(matched),
+		Start:       start,
+	}, nil
+}
+
+// matches は店舗が検索条件に一致するかを返します
+// エリア・ジャンル・予算はコードが一致するもの、位置は検索範囲の中にあるもの、キーワードはすべての語を含むものに絞ります
+func (e csvRestaurant) matches(criteria *entity.SearchCriteria) bool {
+	r := e.restaurant
+	if criteria.ID != "" {
+		return criteria.ID == r.ID
+	}
+	if !codeMatches(criteria.LargeArea, e.largeArea) || !codeMatches(criteria.MiddleArea, e.middleArea) ||
+		!codeMatches(criteria.SmallArea, e.smallArea) || !codeMatches(criteria.Genre, r.Genre.Code) ||
+		!codeMatches(criteria.Budget, r.PriceRange.Code) {
+		return false
+	}
+	if criteria.PartyCapacity > 0 && r.Capacity < criteria.PartyCapacity {
+		return false
+	}
+	if entity.ValidLatLng(criteria.Lat, criteria.Lng) && criteria.RadiusMeters > 0 {
+		if !entity.ValidLatLng(r.Lat, r.Lng) ||
+			entity.HaversineMeters(criteria.Lat, criteria.Lng, r.Lat, r.Lng) > float64(criteria.RadiusMeters) {
+			return false
+		}
+	}
+	for _, facility := range criteria.Facilities {
+		if !r.HasFacility(facility) {
+			return false
+		}
+	}
+	for _, term := range strings.Fields(criteria.Keyword) {
+		if !strings.Contains(e.text, normalizeText(term)) {
+			return false
+		}
+	}
+	return true
+}
+
+// codeMatches は検索条件のコードが指定されていない、または店舗のコードと一致するかを返します
+func codeMatches(want, have string) bool {
+	return want == "" || want == have
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"restaurant-finder/Domain/entity"
+)
+
+// writeCSV はテスト用の CSV ファイルを作成してパスを返します
+func writeCSV(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "favorites.csv")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestNewCSVRestaurantSource(t *testing.T) {
+	source, err := NewCSVRestaurantSource("favorites", "お気に入り", filepath.Join(t.TempDir(), "missing.csv"))
+	if err != nil || source.Len() != 0 {
+		t.Errorf("ファイルがない場合: Len() = %d, err = %v", source.Len(), err)
+	}
+
+	if _, err := NewCSVRestaurantSource("favorites", "お気に入り", writeCSV(t, "id,address\n1,渋谷区\n")); err == nil {
+		t.Error("name の列がない CSV でエラーを返しません")
+	}
+
+	path := writeCSV(t, "\ufeffName,ID,facilities,lat,lng\n"+
+		"焼き鳥 はじめ,,free_drink;PRIVATE_ROOM;karaoke,35.69,139.70\n"+
+		",2,,,\n"+
+		"酒場 ふたば,b,,999,139.70\n")
+	source, err = NewCSVRestaurantSource("favorites", "お気に入り", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if source.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2（name が空の行は読み飛ばす）", source.Len())
+	}
+	first, second := source.entries[0].restaurant, source.entries[1].restaurant
+	if first.ID != "favorites:1" || first.SourceID != "1" {
+		t.Errorf("id の列が空の場合は行番号を ID にします: %+v", first)
+	}
+	if want := []string{entity.FacilityFreeDrink, entity.FacilityPrivateRoom}; !reflect.DeepEqual(first.Facilities, want) {
+		t.Errorf("Facilities = %v, want %v", first.Facilities, want)
+	}
+	if want := []entity.SourceRef{{Source: "favorites", SourceID: "1", Label: "お気に入り"}}; !reflect.DeepEqual(first.Sources, want) {
+		t.Errorf("Sources = %+v, want %+v", first.Sources, want)
+	}
+	if second.ID != "favorites:b" || second.Lat != 0 || second.Lng != 0 {
+		t.Errorf("範囲外の緯度・経度は位置なしにします: %+v", second)
+	}
+}
+
+func TestCSVRestaurantSourceSearch(t *testing.T) {
+	path := writeCSV(t, "id,name,address,lat,lng,middle_area,small_area,genre_code,budget_code,access,facilities,capacity,note\n"+
+		"1,焼き鳥 はじめ,東京都新宿区新宿3-1-1,35.6909,139.7045,Y005,X001,G001,B003,新宿三丁目駅 徒歩2分,free_drink;private_room,40,炭火の焼き鳥\n"+
+		"2,酒場 ふたば,東京都新宿区新宿3-20-1,35.6915,139.7040,Y005,X001,G001,B002,新宿駅 徒歩5分,free_drink,12,\n"+
+		"3,ＴＨＥ ＢＡＲ,東京都渋谷区道玄坂1-1-1,35.6580,139.6990,Y001,X100,G002,B004,渋谷駅 徒歩3分,cocktail,20,\n"+
+		"4,手打ちそば,東京都千代田区丸の内1-1-1,,,Y010,X200,G004,B002,東京駅 徒歩1分,lunch,30,\n")
+	source, err := NewCSVRestaurantSource("favorites", "お気に入り", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		criteria entity.SearchCriteria
+		wantIDs  []string
+	}{
+		{"条件なし", entity.SearchCriteria{}, []string{"favorites:1", "favorites:2", "favorites:3", "favorites:4"}},
+		{"エリアとジャンル", entity.SearchCriteria{MiddleArea: "Y005", Genre: "G001"}, []string{"favorites:1", "favorites:2"}},
+		{"予算", entity.SearchCriteria{Budget: "B002"}, []string{"favorites:2", "favorites:4"}},
+		{"設備・サービス", entity.SearchCriteria{Facilities: []string{entity.FacilityFreeDrink, entity.FacilityPrivateRoom}}, []string{"favorites:1"}},
+		{"宴会の人数", entity.SearchCriteria{PartyCapacity: 25}, []string{"favorites:1", "favorites:4"}},
+		{"半径（位置のない店舗は除く）", entity.SearchCriteria{Lat: 35.6909, Lng: 139.7045, RadiusMeters: 300}, []string{"favorites:1", "favorites:2"}},
+		{"半径が狭い", entity.SearchCriteria{Lat: 35.6909, Lng: 139.7045, RadiusMeters: 50}, []string{"favorites:1"}},
+		{"キーワードは正規化してすべての語を含む", entity.SearchCriteria{Keyword: "the bar 渋谷"}, []string{"favorites:3"}},
+		{"キーワードはメモとアクセスにも一致", entity.SearchCriteria{Keyword: "炭火 新宿三丁目"}, []string{"favorites:1"}},
+		{"ID", entity.SearchCriteria{ID: "favorites:4", Genre: "G001"}, []string{"favorites:4"}},
+		{"ページ", entity.SearchCriteria{Start: 2, Count: 2}, []string{"favorites:2", "favorites:3"}},
+	}
+	for _, tt := range tests {
+		page, err := source.SearchRestaurants(&tt.criteria)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for _, r := range page.Restaurants {
+			ids = append(ids, r.ID)
+		}
+		if !reflect.DeepEqual(ids, tt.wantIDs) {
+			t.Errorf("%s: IDs = %v, want %v", tt.name, ids, tt.wantIDs)
+		}
+	}
+}
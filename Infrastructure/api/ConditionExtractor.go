@@ -0,0 +1,39 @@
+package api
+
+import (
+	"time"
+
+	"restaurant-finder/Domain/entity"
+	"restaurant-finder/Domain/repository"
+)
+
+// QueryConditionExtractor が条件の読み取りのポートを満たすことをコンパイル時に確認する
+var _ repository.ConditionExtractor = QueryConditionExtractor{}
+
+// QueryConditionExtractor は正規表現と辞書でクエリから来店の日時・距離・徒歩の上限などを読み取ります（LLM を使いません）
+type QueryConditionExtractor struct{}
+
+// NewQueryConditionExtractor は QueryConditionExtractor を作成します
+func NewQueryConditionExtractor() QueryConditionExtractor {
+	return QueryConditionExtractor{}
+}
+
+// VisitIntent は来店の日時・人数・食事の種類を読み取ります
+func (QueryConditionExtractor) VisitIntent(text string, now time.Time) entity.VisitIntent {
+	return ExtractVisitIntent(text, now)
+}
+
+// Range は距離の表現を range コードに変換します
+func (QueryConditionExtractor) Range(text string) int {
+	return ExtractRange(text)
+}
+
+// WalkLimit は駅からの徒歩の上限（分）を返します
+func (QueryConditionExtractor) WalkLimit(text string) int {
+	return ExtractWalkLimit(text)
+}
+
+// WantsMidpoint は複数の駅の中間地点で探すことを求めているかを返します
+func (QueryConditionExtractor) WantsMidpoint(text string) bool {
+	return WantsMidpoint(text)
+}
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+	"restaurant-finder/Domain/entity"
+)
+
+// walkingMetersPerMinute は徒歩 1 分あたりの距離（不動産表示の基準）です
+const walkingMetersPerMinute = 80
+
+// proximityWords は距離を表す語と range コードです（先に一致したものを使います）
+var proximityWords = []struct {
+	words []string
+	code  int
+}{
+	{[]string{"すぐ近く", "目の前", "すぐそば", "right nearby"}, 1},
+	{[]string{"近く", "近所", "付近", "そば", "駅前", "駅近", "駅チカ", "nearby", "near me", "close by"}, 2},
+	{[]string{"周辺", "歩いて行ける", "walking distance"}, 3},
+}
+
+var (
+	// walkingMinutesPattern は "徒歩5分以内" "歩いて10分" "5 min walk" のような徒歩の時間です
+	walkingMinutesPattern = regexp.MustCompile(`(?:徒歩|歩いて)\s*(\d{1,2})\s*分|(\d{1,2})\s*(?:min|minute)s?\s*walk`)
+	// distancePattern は "500m以内" "2km圏内" のような距離です
+	distancePattern = regexp.MustCompile(`(?i)(\d+(?:\.\d+)?)\s*(km|m|キロ|メートル)`)
+)
+
+// ExtractRange はクエリの距離の表現（"徒歩5分以内" "近く" "500m以内" など）を range コードに変換します
+// 距離の表現がない場合は 0 を返します
+func ExtractRange(text string) int {
+	text = strings.ToLower(norm.NFKC.String(text))
+
+	if m := walkingMinutesPattern.FindStringSubmatch(text); m != nil {
+		minutes := m[1]
+		if minutes == "" {
+			minutes = m[2]
+		}
+		n, _ := strconv.Atoi(minutes)
+		return entity.RangeForMeters(n * walkingMetersPerMinute)
+	}
+	if m := distancePattern.FindStringSubmatch(text); m != nil {
+		value, _ := strconv.ParseFloat(m[1], 64)
+		if m[2] == "km" || m[2] == "キロ" {
+			value *= 1000
+		}
+		return entity.RangeForMeters(int(value))
+	}
+	for _, pw := range proximityWords {
+		if containsAny(text, pw.words...) {
+			return pw.code
+		}
+	}
+	return 0
+}
@@ -6,30 +6,165 @@ import (
 	"io"
 	"net/http"
 	"net/url"
-	"os"
 	"restaurant-finder/Domain/entity"
+	"restaurant-finder/Domain/repository"
 	"strconv"
+	"time"
 )
 
-type HotPepperAPIClient struct{}
+// HotPepperAPIClient がレストランの提供元のポートを満たすことをコンパイル時に確認する
+var (
+	_ repository.RestaurantSource = (*HotPepperAPIClient)(nil)
+	_ repository.RequestDescriber = (*HotPepperAPIClient)(nil)
+)
+
+// HotPepperAPIClient は HotPepper グルメサーチ API でレストランを検索します
+type HotPepperAPIClient struct {
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewHotPepperAPIClient は API キーを指定して HotPepperAPIClient を作成します
+func NewHotPepperAPIClient(apiKey string) *HotPepperAPIClient {
+	return &HotPepperAPIClient{apiKey: apiKey, httpClient: &http.Client{Timeout: hotPepperTimeout}}
+}
 
-func (c *HotPepperAPIClient) GetRestaurants(params *entity.HotPepperRequestParams) (*entity.HotPepperResponse, error) {
-	hotpepperAPIKey := os.Getenv("HOTPEPPER_API_KEY")
+// Name は提供元の名前を返します
+func (c *HotPepperAPIClient) Name() string {
+	return entity.SourceHotPepper
+}
+
+func (c *HotPepperAPIClient) GetRestaurants(params *HotPepperRequestParams) (*entity.HotPepperResponse, error) {
+	hotpepperAPIKey := c.apiKey
 	if hotpepperAPIKey == "" {
 		return nil, fmt.Errorf("HotPepperAPI通信エラーです。")
 	}
 	fmt.Printf("HotPepper API request params: %+v\n", params)
-	// HotPepperAPIのベースURL
-	baseURL := "https://webservice.recruit.co.jp/hotpepper/gourmet/v1/"
 
-	// クエリパラメータを構築
-	//baseURLの?以降の部分がクエリパラメータ作成のため
+	// APIリクエストを送信
+	fullURL := hotPepperBaseURL + "?" + buildHotPepperQuery(params, hotpepperAPIKey).Encode()
+	fmt.Printf("HotPepper API URL: %s\n", redactedQueryString(params))
+
+	resp, err := c.httpClient.Get(fullURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make API request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	fmt.Printf("HotPepper API response status: %s\n", resp.Status)
+
+	// レスポンスボディを読み取り
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %v", err)
+	}
+
+	// JSONをパース
+	var hotPepperResponse entity.HotPepperResponse
+	if err := json.Unmarshal(body, &hotPepperResponse); err != nil {
+		fmt.Printf("Failed to parse JSON response: %v\n", err)
+		fmt.Printf("Response body: %s\n", string(body))
+		return nil, fmt.Errorf("failed to parse JSON response: %v", err)
+	}
+	fmt.Printf("HotPepper API response parsed successfully. Found %d shops\n", len(hotPepperResponse.Results.Shop))
+
+	return &hotPepperResponse, nil
+}
+
+// SearchRestaurants は検索条件を HotPepper のリクエストパラメータに変換して検索し、レスポンスを 1 ページ分の Restaurant に変換します
+func (c *HotPepperAPIClient) SearchRestaurants(criteria *entity.SearchCriteria) (*entity.RestaurantPage, error) {
+	params := hotPepperParams(criteria)
+	response, err := c.GetRestaurants(params)
+	if err != nil {
+		return nil, err
+	}
+	page := &entity.RestaurantPage{
+		Restaurants: RestaurantsFromShops(response.Results.Shop),
+		Available:   response.Results.ResultsAvailable,
+		Start:       response.Results.ResultsStart,
+	}
+	if page.Start == 0 {
+		page.Start = max(params.Start, 1)
+	}
+	return page, nil
+}
+
+// hotPepperParams は検索条件を HotPepper API のリクエストパラメータに変換します
+// 半径はそれを含む最小の range コードに、必須の設備・サービスはそれぞれのパラメータにします
+func hotPepperParams(criteria *entity.SearchCriteria) *HotPepperRequestParams {
+	params := &HotPepperRequestParams{
+		ID:            criteria.ID,
+		Keyword:       criteria.Keyword,
+		LargeArea:     criteria.LargeArea,
+		MiddleArea:    criteria.MiddleArea,
+		SmallArea:     criteria.SmallArea,
+		Genre:         criteria.Genre,
+		Budget:        criteria.Budget,
+		PartyCapacity: criteria.PartyCapacity,
+		Start:         criteria.Start,
+		Count:         criteria.Count,
+	}
+	if entity.ValidLatLng(criteria.Lat, criteria.Lng) {
+		params.Lat, params.Lng = criteria.Lat, criteria.Lng
+		params.Range = entity.DefaultRange
+		if criteria.RadiusMeters > 0 {
+			params.Range = entity.RangeForMeters(criteria.RadiusMeters)
+		}
+	}
+	for _, facility := range criteria.Facilities {
+		if flag := hotPepperFacilityParam(params, facility); flag != nil {
+			*flag = 1
+		}
+	}
+	return params
+}
+
+// hotPepperFacilityParam は設備・サービスに対応する HotPepper API のパラメータを返します（対応するものがない場合は nil）
+func hotPepperFacilityParam(params *HotPepperRequestParams, facility string) *int {
+	switch facility {
+	case entity.FacilityLunch:
+		return &params.Lunch
+	case entity.FacilityPrivateRoom:
+		return &params.PrivateRoom
+	case entity.FacilityFreeDrink:
+		return &params.Free_drink
+	case entity.FacilityFreeFood:
+		return &params.Free_food
+	case entity.FacilityMidnight:
+		return &params.Midnight
+	case entity.FacilitySake:
+		return &params.Sake
+	case entity.FacilityCocktail:
+		return &params.Cacktail
+	case entity.FacilityWine:
+		return &params.Wine
+	case entity.FacilityEnglish:
+		return &params.English
+	}
+	return nil
+}
+
+// hotPepperBaseURL は HotPepper グルメサーチ API のベースURLです
+const hotPepperBaseURL = "https://webservice.recruit.co.jp/hotpepper/gourmet/v1/"
+
+// hotPepperTimeout は HotPepper API への 1 回のリクエストのタイムアウトです
+const hotPepperTimeout = 10 * time.Second
+
+// redactedAPIKey はログや explain 表示で API キーの代わりに使う文字列です
+const redactedAPIKey = "REDACTED"
+
+// buildHotPepperQuery は検索パラメータから HotPepper API のクエリパラメータを構築します
+// baseURLの?以降の部分がクエリパラメータ作成のため
+func buildHotPepperQuery(params *HotPepperRequestParams, key string) url.Values {
 	queryParams := url.Values{}
 	//APIきー。?key以降
-	queryParams.Set("key", hotpepperAPIKey)
+	queryParams.Set("key", key)
 	queryParams.Set("format", "json")
 
 	// パラメータが設定されている場合のみ追加
+	if params.ID != "" {
+		queryParams.Set("id", params.ID)
+	}
 	if params.Keyword != "" {
 		queryParams.Set("keyword", params.Keyword)
 	}
@@ -37,7 +172,7 @@ func (c *HotPepperAPIClient) GetRestaurants(params *entity.HotPepperRequestParam
 		queryParams.Set("lat", strconv.FormatFloat(params.Lat, 'f', -1, 64))
 	}
 	if params.Lng != 0 {
-		queryParams.Set("lng", fmt.Sprintf("%f", params.Lng))
+		queryParams.Set("lng", strconv.FormatFloat(params.Lng, 'f', -1, 64))
 	}
 	if params.Range != 0 {
 		queryParams.Set("range", fmt.Sprintf("%d", params.Range))
@@ -79,41 +214,36 @@ func (c *HotPepperAPIClient) GetRestaurants(params *entity.HotPepperRequestParam
 		queryParams.Set("midnight", fmt.Sprintf("%d", params.Midnight))
 	}
 	if params.Cacktail != 0 {
-		queryParams.Set("Cacktail", fmt.Sprintf("%d", params.Cacktail))
+		queryParams.Set("cocktail", fmt.Sprintf("%d", params.Cacktail))
 	}
 	if params.Sake != 0 {
-		queryParams.Set("Sake", fmt.Sprintf("%d", params.Sake))
+		queryParams.Set("sake", fmt.Sprintf("%d", params.Sake))
 	}
 	if params.Wine != 0 {
-		queryParams.Set("Wine", fmt.Sprintf("%d", params.Wine))
+		queryParams.Set("wine", fmt.Sprintf("%d", params.Wine))
 	}
-
-	// APIリクエストを送信
-	fullURL := baseURL + "?" + queryParams.Encode()
-	fmt.Printf("HotPepper API URL: %s\n", fullURL)
-
-	resp, err := http.Get(fullURL)
-	if err != nil {
-		return nil, fmt.Errorf("failed to make API request: %v", err)
+	if params.English != 0 {
+		queryParams.Set("english", fmt.Sprintf("%d", params.English))
+	}
+	if params.PartyCapacity != 0 {
+		queryParams.Set("party_capacity", fmt.Sprintf("%d", params.PartyCapacity))
 	}
-	defer resp.Body.Close()
 
-	fmt.Printf("HotPepper API response status: %s\n", resp.Status)
+	return queryParams
+}
 
-	// レスポンスボディを読み取り
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %v", err)
+// DescribeRequest は検索条件で API に送るリクエスト URL を、API キーを伏せ字にして返します
+func (c *HotPepperAPIClient) DescribeRequest(criteria *entity.SearchCriteria) string {
+	if criteria == nil {
+		return ""
 	}
+	return redactedQueryString(hotPepperParams(criteria))
+}
 
-	// JSONをパース
-	var hotPepperResponse entity.HotPepperResponse
-	if err := json.Unmarshal(body, &hotPepperResponse); err != nil {
-		fmt.Printf("Failed to parse JSON response: %v\n", err)
-		fmt.Printf("Response body: %s\n", string(body))
-		return nil, fmt.Errorf("failed to parse JSON response: %v", err)
+// redactedQueryString は API に送るリクエスト URL を、API キーを伏せ字にして返します
+func redactedQueryString(params *HotPepperRequestParams) string {
+	if params == nil {
+		return ""
 	}
-	fmt.Printf("HotPepper API response parsed successfully. Found %d shops\n", len(hotPepperResponse.Results.Shop))
-
-	return &hotPepperResponse, nil
+	return hotPepperBaseURL + "?" + buildHotPepperQuery(params, redactedAPIKey).Encode()
 }
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"testing"
+
+	"restaurant-finder/Domain/entity"
+)
+
+func TestHotPepperParams(t *testing.T) {
+	tests := []struct {
+		name     string
+		criteria entity.SearchCriteria
+		want     HotPepperRequestParams
+	}{
+		{
+			"コードとページ",
+			entity.SearchCriteria{ID: "J001", Keyword: "焼き鳥", MiddleArea: "Y005", Genre: "G001", Start: 11, Count: 10},
+			HotPepperRequestParams{ID: "J001", Keyword: "焼き鳥", MiddleArea: "Y005", Genre: "G001", Start: 11, Count: 10},
+		},
+		{
+			"半径はそれを含む最小の range コードにする",
+			entity.SearchCriteria{Lat: 35.69, Lng: 139.70, RadiusMeters: 400},
+			HotPepperRequestParams{Lat: 35.69, Lng: 139.70, Range: 2},
+		},
+		{
+			"半径の指定がない場合は既定の range",
+			entity.SearchCriteria{Lat: 35.69, Lng: 139.70},
+			HotPepperRequestParams{Lat: 35.69, Lng: 139.70, Range: entity.DefaultRange},
+		},
+		{
+			"位置がない場合は半径を使わない",
+			entity.SearchCriteria{RadiusMeters: 3000},
+			HotPepperRequestParams{},
+		},
+		{
+			"設備・サービスはそれぞれのパラメータにする",
+			entity.SearchCriteria{Facilities: []string{entity.FacilityFreeDrink, entity.FacilityCocktail, entity.FacilityEnglish, "unknown"}},
+			HotPepperRequestParams{Free_drink: 1, Cacktail: 1, English: 1},
+		},
+	}
+	for _, tt := range tests {
+		if got := hotPepperParams(&tt.criteria); *got != tt.want {
+			t.Errorf("%s: hotPepperParams() = %+v, want %+v", tt.name, *got, tt.want)
+		}
+	}
+}
+
+func TestDescribeRequestRedactsKey(t *testing.T) {
+	client := NewHotPepperAPIClient("secret-key")
+	got := client.DescribeRequest(&entity.SearchCriteria{Keyword: "ramen", Facilities: []string{entity.FacilityLunch}})
+	want := hotPepperBaseURL + "?format=json&key=" + redactedAPIKey + "&keyword=ramen&lunch=1"
+	if got != want {
+		t.Errorf("DescribeRequest() = %q, want %q", got, want)
+	}
+}
+
+func TestBuildHotPepperQueryFlags(t *testing.T) {
+	// 設備・サービスはすべて HotPepper API の小文字のパラメータ名で送る
+	tests := []struct {
+		facility string
+		key      string
+	}{
+		{entity.FacilityLunch, "lunch"},
+		{entity.FacilityPrivateRoom, "private_room"},
+		{entity.FacilityFreeDrink, "free_drink"},
+		{entity.FacilityFreeFood, "free_food"},
+		{entity.FacilityMidnight, "midnight"},
+		{entity.FacilitySake, "sake"},
+		{entity.FacilityCocktail, "cocktail"},
+		{entity.FacilityWine, "wine"},
+		{entity.FacilityEnglish, "english"},
+	}
+	if len(tests) != len(entity.FacilityOptions) {
+		t.Fatalf("設備・サービス %d 件のうち %d 件しか確認していません", len(entity.FacilityOptions), len(tests))
+	}
+	for _, tt := range tests {
+		query := buildHotPepperQuery(hotPepperParams(&entity.SearchCriteria{Facilities: []string{tt.facility}}), "k")
+		if got := query.Get(tt.key); got != "1" {
+			t.Errorf("%s: %s = %q, want \"1\" (query %s)", tt.facility, tt.key, got, query.Encode())
+		}
+		if len(query) != 3 {
+			t.Errorf("%s: 余分なパラメータがあります: %s", tt.facility, query.Encode())
+		}
+	}
+}
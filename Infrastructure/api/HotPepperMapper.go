@@ -0,0 +1,113 @@
+package api
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"restaurant-finder/Domain/entity"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// hotPepperSourceLabel は画面に表示する HotPepper の提供元の名前です
+const hotPepperSourceLabel = "ホットペッパーグルメ"
+
+// priceRangePattern は予算の名称（"2001～3000円" / "～500円" / "30001円～"）の金額の範囲です
+var priceRangePattern = regexp.MustCompile(`(\d+)?\s*円?\s*[~〜]\s*(\d+)?`)
+
+// RestaurantsFromShops は HotPepper の店舗の一覧を Restaurant に変換します
+func RestaurantsFromShops(shops []entity.Shop) []entity.Restaurant {
+	restaurants := make([]entity.Restaurant, 0, len(shops))
+	for _, shop := range shops {
+		restaurants = append(restaurants, RestaurantFromShop(shop))
+	}
+	return restaurants
+}
+
+// RestaurantFromShop は HotPepper の店舗を提供元に依存しない Restaurant に変換します
+// 営業時間・アクセス・予算・設備の文章はここで解析し、以降の処理では HotPepper の形式を扱いません
+func RestaurantFromShop(shop entity.Shop) entity.Restaurant {
+	restaurant := entity.Restaurant{
+		ID:         shop.ID,
+		Source:     entity.SourceHotPepper,
+		SourceID:   shop.ID,
+		Name:       shop.Name,
+		Catch:      shop.Catch,
+		Address:    shop.Address,
+		Lat:        shop.Lat,
+		Lng:        shop.Lng,
+		URL:        shop.URLs.PC,
+		PhotoURL:   shopPhotoURL(shop),
+		Access:     shop.Access,
+		AccessInfo: ParseAccess(shop.Access),
+		Genre:      entity.Category{Code: shop.Genre.Code, Name: shop.Genre.Name},
+		PriceRange: parsePriceRange(shop.Budget.Code, shop.Budget.Name),
+		OpenText:   shop.Open,
+		CloseText:  shop.Close,
+		Facilities: shopFacilities(shop),
+		Capacity:   int(shop.Capacity),
+		Sources: []entity.SourceRef{
+			{Source: entity.SourceHotPepper, SourceID: shop.ID, Label: hotPepperSourceLabel, URL: shop.URLs.PC},
+		},
+	}
+	if hours := ParseOpeningHours(shop.Open, shop.Close); hours != nil && hours.Parsed {
+		restaurant.Hours = hours
+	}
+	return restaurant
+}
+
+// shopPhotoURL は店舗の写真のうち最も大きいものを返します
+func shopPhotoURL(shop entity.Shop) string {
+	for _, url := range []string{shop.Photo.PC.L, shop.Photo.PC.M, shop.Photo.Mobile.L, shop.Photo.PC.S, shop.Photo.Mobile.S} {
+		if url != "" {
+			return url
+		}
+	}
+	return ""
+}
+
+// shopFacilities は「あり」の設備・サービスの名前を返します
+func shopFacilities(shop entity.Shop) []string {
+	values := []struct {
+		name  string
+		value string
+	}{
+		{entity.FacilityLunch, shop.Lunch},
+		{entity.FacilityPrivateRoom, shop.PrivateRoom},
+		{entity.FacilityFreeDrink, shop.FreeDrink},
+		{entity.FacilityFreeFood, shop.FreeFood},
+		{entity.FacilityMidnight, shop.Midnight},
+		{entity.FacilitySake, shop.Sake},
+		{entity.FacilityCocktail, shop.Cocktail},
+		{entity.FacilityWine, shop.Wine},
+		{entity.FacilityEnglish, shop.English},
+	}
+	facilities := make([]string, 0, len(values))
+	for _, v := range values {
+		if facilityAvailable(v.value) {
+			facilities = append(facilities, v.name)
+		}
+	}
+	return facilities
+}
+
+// facilityAvailable は設備・サービスの値が「あり」を示しているかを返します
+// HotPepper は "あり" / "あり：飲み放題2000円～" / "営業している" などの文字列で返します
+func facilityAvailable(value string) bool {
+	value = strings.TrimSpace(value)
+	return strings.HasPrefix(value, "あり") || value == "営業している"
+}
+
+// parsePriceRange は予算の名称から 1 人あたりの金額の範囲を読み取ります
+func parsePriceRange(code, name string) entity.PriceRange {
+	price := entity.PriceRange{Code: code, Name: name}
+	text := strings.ReplaceAll(norm.NFKC.String(name), ",", "")
+	match := priceRangePattern.FindStringSubmatch(text)
+	if match == nil {
+		return price
+	}
+	price.Min, _ = strconv.Atoi(match[1])
+	price.Max, _ = strconv.Atoi(match[2])
+	return price
+}
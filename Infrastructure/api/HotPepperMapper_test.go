@@ -0,0 +1,150 @@
+package api
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"restaurant-finder/Domain/entity"
+)
+
+// decodeShop は HotPepper のレスポンスの店舗の JSON を Shop に変換します
+func decodeShop(t *testing.T, data string) entity.Shop {
+	t.Helper()
+	var shop entity.Shop
+	if err := json.Unmarshal([]byte(data), &shop); err != nil {
+		t.Fatal(err)
+	}
+	return shop
+}
+
+func TestRestaurantFromShop(t *testing.T) {
+	shop := decodeShop(t, `{
+		"id": "J001234567",
+		"name": "焼き鳥 とり源",
+		"urls": {"pc": "https://www.hotpepper.jp/strJ001234567/"},
+		"address": "東京都新宿区新宿3-1-1",
+		"lat": 35.6909, "lng": 139.7045,
+		"photo": {"pc": {"l": "", "m": "https://example.com/m.jpg", "s": "https://example.com/s.jpg"}, "mobile": {"l": "https://example.com/ml.jpg"}},
+		"access": "新宿三丁目駅C3出口徒歩2分",
+		"open": "月~金: 17:00~翌2:00",
+		"close": "日",
+		"genre": {"code": "G001", "name": "居酒屋"},
+		"catch": "炭火で焼く焼き鳥",
+		"budget": {"code": "B003", "name": "3001～4000円"},
+		"private_room": "あり：10名様まで",
+		"free_drink": "なし",
+		"lunch": "なし",
+		"midnight": "営業している",
+		"sake": "あり",
+		"english": "",
+		"capacity": "40"
+	}`)
+
+	got := RestaurantFromShop(shop)
+
+	want := entity.Restaurant{
+		ID:         "J001234567",
+		Source:     entity.SourceHotPepper,
+		SourceID:   "J001234567",
+		Name:       "焼き鳥 とり源",
+		Catch:      "炭火で焼く焼き鳥",
+		Address:    "東京都新宿区新宿3-1-1",
+		Lat:        35.6909,
+		Lng:        139.7045,
+		URL:        "https://www.hotpepper.jp/strJ001234567/",
+		PhotoURL:   "https://example.com/m.jpg",
+		Access:     "新宿三丁目駅C3出口徒歩2分",
+		AccessInfo: ParseAccess("新宿三丁目駅C3出口徒歩2分"),
+		Genre:      entity.Category{Code: "G001", Name: "居酒屋"},
+		PriceRange: entity.PriceRange{Code: "B003", Name: "3001～4000円", Min: 3001, Max: 4000},
+		OpenText:   "月~金: 17:00~翌2:00",
+		CloseText:  "日",
+		Facilities: []string{entity.FacilityPrivateRoom, entity.FacilityMidnight, entity.FacilitySake},
+		Capacity:   40,
+		Sources: []entity.SourceRef{
+			{Source: entity.SourceHotPepper, SourceID: "J001234567", Label: hotPepperSourceLabel, URL: "https://www.hotpepper.jp/strJ001234567/"},
+		},
+	}
+	if got.Hours == nil {
+		t.Error("営業時間を解析していません")
+	}
+	got.Hours = nil
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RestaurantFromShop() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestRestaurantFromShopWithoutDetails(t *testing.T) {
+	got := RestaurantFromShop(decodeShop(t, `{"id": "J1", "name": "店", "open": "", "capacity": ""}`))
+	if got.Hours != nil || got.Capacity != 0 || len(got.Facilities) != 0 || got.PhotoURL != "" || got.PriceRange != (entity.PriceRange{}) {
+		t.Errorf("RestaurantFromShop() = %+v", got)
+	}
+	if got := RestaurantsFromShops(nil); got == nil || len(got) != 0 {
+		t.Errorf("RestaurantsFromShops(nil) = %#v, want 空の一覧", got)
+	}
+}
+
+func TestShopPhotoURL(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want string
+	}{
+		{"PC の大きい写真", `{"photo": {"pc": {"l": "pc-l", "m": "pc-m"}, "mobile": {"l": "mobile-l"}}}`, "pc-l"},
+		{"PC の中くらいの写真", `{"photo": {"pc": {"m": "pc-m", "s": "pc-s"}, "mobile": {"l": "mobile-l"}}}`, "pc-m"},
+		{"携帯の大きい写真", `{"photo": {"pc": {"s": "pc-s"}, "mobile": {"l": "mobile-l"}}}`, "mobile-l"},
+		{"PC の小さい写真", `{"photo": {"pc": {"s": "pc-s"}, "mobile": {"s": "mobile-s"}}}`, "pc-s"},
+		{"携帯の小さい写真", `{"photo": {"mobile": {"s": "mobile-s"}}}`, "mobile-s"},
+		{"写真なし", `{}`, ""},
+	}
+	for _, tt := range tests {
+		if got := shopPhotoURL(decodeShop(t, tt.json)); got != tt.want {
+			t.Errorf("%s: shopPhotoURL() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFacilityAvailable(t *testing.T) {
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{"あり", true},
+		{"あり：飲み放題2000円～", true},
+		{" あり ", true},
+		{"営業している", true},
+		{"なし", false},
+		{"営業していない", false},
+		{"未確認", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := facilityAvailable(tt.value); got != tt.want {
+			t.Errorf("facilityAvailable(%q) = %t, want %t", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParsePriceRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		min, max int
+	}{
+		{"2001～3000円", 2001, 3000},
+		{"～500円", 0, 500},
+		{"30001円～", 30001, 0},
+		{"1,001～1,500円", 1001, 1500},
+		{"１００１～１５００円", 1001, 1500},
+		{"2001~3000円", 2001, 3000},
+		{"予算未設定", 0, 0},
+		{"", 0, 0},
+	}
+	for _, tt := range tests {
+		got := parsePriceRange("B001", tt.name)
+		want := entity.PriceRange{Code: "B001", Name: tt.name, Min: tt.min, Max: tt.max}
+		if got != want {
+			t.Errorf("parsePriceRange(%q) = %+v, want %+v", tt.name, got, want)
+		}
+	}
+}
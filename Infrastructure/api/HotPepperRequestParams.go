@@ -1,9 +1,10 @@
-package entity
+package api
 
 // HotPepperRequestParams は、Hot Pepper グルメサーチAPIのリクエストパラメータを定義します。
+// アプリ内では entity.SearchCriteria を使い、API に送る直前に hotPepperParams で変換します
 type HotPepperRequestParams struct {
-	Key         string  `json:"key"`
-	Format      string  `json:"format"`
+	Key         string  `json:"key,omitempty"`
+	Format      string  `json:"format,omitempty"`
 	Keyword     string  `json:"keyword,omitempty"`
 	Lat         float64 `json:"lat,omitempty"`
 	Lng         float64 `json:"lng,omitempty"`
@@ -23,4 +24,9 @@ type HotPepperRequestParams struct {
 	Cacktail    int     `json:"cacktail,omitempty"`
 	Sake        int     `json:"sake,omitempty"`
 	Wine        int     `json:"wine,omitempty"`
+	English     int     `json:"english,omitempty"`
+	// PartyCapacity は宴会収容人数（この人数以上を収容できる店舗を検索）です
+	PartyCapacity int `json:"party_capacity,omitempty"`
+	// ID は店舗 ID です（指定した場合はその店舗だけを返します）
+	ID string `json:"id,omitempty"`
 }
@@ -0,0 +1,159 @@
+package api
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"restaurant-finder/Domain/entity"
+)
+
+// languageNames は説明文の生成時にモデルへ伝える言語名です
+var languageNames = map[string]string{
+	entity.LanguageJapanese: "日本語",
+	entity.LanguageEnglish:  "英語（English）",
+	entity.LanguageChinese:  "中国語（简体中文）",
+	entity.LanguageKorean:   "韓国語（한국어）",
+}
+
+// termTranslations は外国語の地名・ジャンルをマスタデータの日本語名称に変換する辞書です
+// LLM を使わない場合や、LLM が翻訳せずに返した場合の補完に使います
+var termTranslations = map[string]string{
+	// 地名（英語）
+	"tokyo":      "東京",
+	"shinjuku":   "新宿",
+	"shibuya":    "渋谷",
+	"ikebukuro":  "池袋",
+	"ginza":      "銀座",
+	"roppongi":   "六本木",
+	"akihabara":  "秋葉原",
+	"ueno":       "上野",
+	"asakusa":    "浅草",
+	"shinagawa":  "品川",
+	"ebisu":      "恵比寿",
+	"omotesando": "表参道",
+	"harajuku":   "原宿",
+	"yokohama":   "横浜",
+	"osaka":      "大阪",
+	"umeda":      "梅田",
+	"namba":      "難波",
+	"kyoto":      "京都",
+	"kobe":       "神戸",
+	"nagoya":     "名古屋",
+	"fukuoka":    "福岡",
+	"hakata":     "博多",
+	"sapporo":    "札幌",
+	// 地名（中国語）
+	"东京":  "東京",
+	"涩谷":  "渋谷",
+	"银座":  "銀座",
+	"秋叶原": "秋葉原",
+	"难波":  "難波",
+	// 地名（韓国語）
+	"도쿄":    "東京",
+	"신주쿠":   "新宿",
+	"시부야":   "渋谷",
+	"이케부쿠로": "池袋",
+	"긴자":    "銀座",
+	"롯폰기":   "六本木",
+	"아키하바라": "秋葉原",
+	"아사쿠사":  "浅草",
+	"오사카":   "大阪",
+	"난바":    "難波",
+	"교토":    "京都",
+	// ジャンル（英語）
+	"izakaya":  "居酒屋",
+	"italian":  "イタリアン",
+	"french":   "フレンチ",
+	"chinese":  "中華",
+	"korean":   "韓国料理",
+	"japanese": "和食",
+	"ramen":    "ラーメン",
+	"sushi":    "寿司",
+	"yakiniku": "焼肉",
+	"bbq":      "焼肉",
+	"yakitori": "焼き鳥",
+	"tempura":  "天ぷら",
+	"cafe":     "カフェ",
+	"bar":      "バー",
+	// 条件（英語）
+	"cheap":       "安い",
+	"inexpensive": "安い",
+	// ジャンル（中国語）
+	"拉面":   "ラーメン",
+	"烤肉":   "焼肉",
+	"中餐":   "中華",
+	"韩国料理": "韓国料理",
+	"意大利菜": "イタリアン",
+	"咖啡":   "カフェ",
+	// ジャンル（韓国語）
+	"이자카야": "居酒屋",
+	"라멘":   "ラーメン",
+	"스시":   "寿司",
+	"초밥":   "寿司",
+	"야키니쿠": "焼肉",
+	"고기":   "焼肉",
+	"카페":   "カフェ",
+}
+
+// substringTerms は語の区切りがなくても部分一致で置き換える辞書の語です（中国語・韓国語）
+// 短い語が長い語の一部を先に置き換えないよう、長い語から順に並べます
+var substringTerms = func() []string {
+	terms := make([]string, 0, len(termTranslations))
+	for term := range termTranslations {
+		if term[0] >= utf8.RuneSelf {
+			terms = append(terms, term)
+		}
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if li, lj := utf8.RuneCountInString(terms[i]), utf8.RuneCountInString(terms[j]); li != lj {
+			return li > lj
+		}
+		return terms[i] < terms[j]
+	})
+	return terms
+}()
+
+// englishStopWords はキーワードに残しても検索の役に立たない英語の語です（LLM を使わない場合に除きます）
+var englishStopWords = map[string]bool{
+	"a": true, "an": true, "the": true, "in": true, "at": true, "near": true, "around": true, "by": true,
+	"on": true, "of": true, "for": true, "with": true, "and": true, "to": true, "some": true, "any": true,
+	"i": true, "me": true, "we": true, "want": true, "looking": true, "find": true, "show": true, "please": true,
+	"where": true, "is": true, "are": true, "can": true, "eat": true, "good": true, "best": true, "nice": true,
+	"restaurant": true, "restaurants": true, "place": true, "places": true, "food": true, "spot": true, "spots": true,
+	"station": true, "area": true,
+}
+
+// translateTerm は外国語の地名・ジャンルを日本語の名称に変換します。辞書にない場合はそのまま返します
+func translateTerm(term string) string {
+	if ja, ok := termTranslations[strings.ToLower(strings.TrimSpace(term))]; ok {
+		return ja
+	}
+	return term
+}
+
+// translatePrompt はクエリ中の既知の外国語の語を日本語に置き換え、英語の前置詞などを除きます（LLM を使わない場合に使用）
+func translatePrompt(prompt string) string {
+	words := make([]string, 0)
+	for _, w := range strings.Fields(prompt) {
+		w = strings.Trim(w, ",.!?、。")
+		if w == "" || englishStopWords[strings.ToLower(w)] {
+			continue
+		}
+		words = append(words, translateTerm(w))
+	}
+	translated := strings.Join(words, " ")
+
+	// 中国語・韓国語は語が空白で区切られていないことがあるため部分一致でも置き換える
+	for _, term := range substringTerms {
+		translated = strings.ReplaceAll(translated, term, termTranslations[term])
+	}
+	return translated
+}
+
+// applyLanguage は日本語以外のクエリに英語メニューありの条件を付けます
+func applyLanguage(params *entity.SearchCriteria, language string) {
+	if language != entity.LanguageJapanese {
+		params.SetFacility(entity.FacilityEnglish, true)
+	}
+}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"testing"
+	"unicode/utf8"
+
+	"restaurant-finder/Domain/entity"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"渋谷 居酒屋", entity.LanguageJapanese},
+		{"新宿方面 居酒屋", entity.LanguageJapanese},
+		{"東京駅附近 中華", entity.LanguageJapanese},
+		{"梅田 家庭的 和食", entity.LanguageJapanese},
+		{"池袋 個室 飲放題", entity.LanguageJapanese},
+		{"新宿で安い焼き鳥", entity.LanguageJapanese},
+		{"东京 好吃的拉面", entity.LanguageChinese},
+		{"涩谷附近的餐厅", entity.LanguageChinese},
+		{"推荐火锅", entity.LanguageChinese},
+		{"cheap izakaya in shinjuku", entity.LanguageEnglish},
+		{"시부야 이자카야", entity.LanguageKorean},
+		{"", entity.LanguageJapanese},
+	}
+	for _, tt := range tests {
+		if got := entity.DetectLanguage(tt.text); got != tt.want {
+			t.Errorf("entity.DetectLanguage(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestApplyLanguage(t *testing.T) {
+	params := &entity.SearchCriteria{}
+	applyLanguage(params, entity.DetectLanguage("新宿方面 居酒屋"))
+	if params.RequiresFacility(entity.FacilityEnglish) {
+		t.Errorf("日本語のクエリで english が付きました: %v", params.Facilities)
+	}
+	applyLanguage(params, entity.LanguageEnglish)
+	if !params.RequiresFacility(entity.FacilityEnglish) {
+		t.Errorf("英語のクエリで english が付きません: %v", params.Facilities)
+	}
+}
+
+func TestTranslatePrompt(t *testing.T) {
+	tests := []struct {
+		prompt string
+		want   string
+	}{
+		{"izakaya in Shibuya", "居酒屋 渋谷"},
+		{"cheap izakaya in shinjuku", "安い 居酒屋 新宿"},
+		{"the best ramen near Tokyo Station!", "ラーメン 東京"},
+		{"quiet bar", "quiet バー"},
+		{"东京拉面", "東京ラーメン"},
+		{"시부야 이자카야", "渋谷 居酒屋"},
+	}
+	for _, tt := range tests {
+		if got := translatePrompt(tt.prompt); got != tt.want {
+			t.Errorf("translatePrompt(%q) = %q, want %q", tt.prompt, got, tt.want)
+		}
+	}
+}
+
+func TestSubstringTermsLongestFirst(t *testing.T) {
+	// 部分一致の置き換えは長い語から順に行う（"韩国料理" の一部を先に置き換えない）
+	for i := 1; i < len(substringTerms); i++ {
+		if utf8.RuneCountInString(substringTerms[i-1]) < utf8.RuneCountInString(substringTerms[i]) {
+			t.Fatalf("%q が %q より前にあります", substringTerms[i-1], substringTerms[i])
+		}
+	}
+	if got := translatePrompt("韩国料理"); got != "韓国料理" {
+		t.Errorf("translatePrompt(韩国料理) = %q", got)
+	}
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"sync"
+
+	"restaurant-finder/Domain/entity"
+)
+
+// FormatMasterDataRepository は format.json からマスタデータを読み込むリポジトリです
+// format.json は最初に使うときに 1 回だけ読み込み、以降は読み込んだマスタデータを返します
+type FormatMasterDataRepository struct {
+	load   func() (*entity.MasterData, error)
+	once   sync.Once
+	master *entity.MasterData
+	err    error
+}
+
+// NewFormatMasterDataRepository は FormatMasterDataRepository を作成します
+func NewFormatMasterDataRepository() *FormatMasterDataRepository {
+	return &FormatMasterDataRepository{load: LoadMasterData}
+}
+
+// MasterData は format.json から読み込んだ選択肢を返します（呼び出し側は変更しないでください）
+func (r *FormatMasterDataRepository) MasterData() (*entity.MasterData, error) {
+	r.once.Do(func() {
+		r.master, r.err = r.load()
+	})
+	return r.master, r.err
+}
+
+// LoadMasterData は format.json を読み込んで選択肢を作成します
+func LoadMasterData() (*entity.MasterData, error) {
+	fmtData, err := loadFormatJSON()
+	if err != nil {
+		return &entity.MasterData{}, err
+	}
+	return newMasterData(extractFormatJSONToSlices(fmtData)), nil
+}
+
+// newMasterData は format.json のスライスから選択肢を作成します
+func newMasterData(fmtSlices map[string][]map[string]interface{}) *entity.MasterData {
+	return &entity.MasterData{
+		LargeAreas:  masterOptions("large_area", fmtSlices["large_area"]),
+		MiddleAreas: masterOptions("middle_area", fmtSlices["middle_area"]),
+		SmallAreas:  masterOptions("small_area", fmtSlices["small_area"]),
+		Genres:      masterOptions("genre", fmtSlices["genre"]),
+		Budgets:     masterOptions("budget", fmtSlices["budget"]),
+	}
+}
+
+// masterOptions は format.json の項目を選択肢に変換します
+func masterOptions(category string, items []map[string]interface{}) []entity.MasterOption {
+	options := make([]entity.MasterOption, 0, len(items))
+	for _, item := range items {
+		c, ok := candidateFromItem(category, item)
+		if !ok {
+			continue
+		}
+		option := entity.MasterOption{Code: c.Code, Name: c.Name}
+		switch category {
+		case "middle_area":
+			option.ParentCode = c.LargeAreaCode
+		case "small_area":
+			option.ParentCode = c.MiddleAreaCode
+		}
+		options = append(options, option)
+	}
+	return options
+}
@@ -0,0 +1,26 @@
+package api
+
+import (
+	"testing"
+
+	"restaurant-finder/Domain/entity"
+)
+
+func TestFormatMasterDataRepositoryLoadsOnce(t *testing.T) {
+	loads := 0
+	repo := &FormatMasterDataRepository{load: func() (*entity.MasterData, error) {
+		loads++
+		return &entity.MasterData{Genres: []entity.MasterOption{{Code: "G001", Name: "居酒屋"}}}, nil
+	}}
+	first, err := repo.MasterData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := repo.MasterData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loads != 1 || first != second {
+		t.Errorf("loads = %d, same = %t, want 1 and true", loads, first == second)
+	}
+}
@@ -0,0 +1,335 @@
+package api
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"restaurant-finder/Domain/entity"
+)
+
+const (
+	// minMatchScore は候補として残す最低スコアです
+	minMatchScore = 0.45
+	// ambiguityMargin は最上位の候補とこの差以内のスコアの候補を同程度とみなす幅です
+	ambiguityMargin = 0.05
+	// maxCandidates は 1 つの語について保持する候補の最大数です
+	maxCandidates = 10
+)
+
+// areaLevels はエリアの区分と、同点時の優先順位（小区分を優先）です
+var areaLevels = map[string]int{
+	"small_area":  0,
+	"middle_area": 1,
+	"large_area":  2,
+}
+
+// matchReport はマッピングの結果です
+type matchReport struct {
+	// Codes は項目ごとに採用したコードです
+	Codes map[string]string
+	// Candidates は抽出項目ごとのスコア付き候補です
+	Candidates map[string][]entity.CodeCandidate
+	// Ambiguities は最上位の候補が一意に決まらなかった項目です
+	Ambiguities []entity.Ambiguity
+	// Keyword は検索キーワード（同義語による追加を含む）です
+	Keyword string
+	// Extracted はマッチングに使った抽出項目です
+	Extracted map[string]string
+	// AppliedSynonyms は適用した同義語です
+	AppliedSynonyms []entity.Synonym
+}
+
+func newMatchReport() *matchReport {
+	return &matchReport{
+		Codes:      make(map[string]string),
+		Candidates: make(map[string][]entity.CodeCandidate),
+	}
+}
+
+// scoreName は正規化済みの 2 つの名前の一致度を 0〜1 で返します
+// 完全一致 > 前方一致 > 部分一致 > n-gram・編集距離による類似の順に高くなります
+func scoreName(term, name string) float64 {
+	if term == "" || name == "" {
+		return 0
+	}
+	if term == name {
+		return 1.0
+	}
+
+	termLen, nameLen := utf8.RuneCountInString(term), utf8.RuneCountInString(name)
+	ratio := float64(min(termLen, nameLen)) / float64(max(termLen, nameLen))
+
+	if strings.HasPrefix(name, term) || strings.HasPrefix(term, name) {
+		return 0.75 + 0.2*ratio
+	}
+	if strings.Contains(name, term) || strings.Contains(term, name) {
+		return 0.6 + 0.2*ratio
+	}
+	return 0.7 * max(bigramSimilarity(term, name), editSimilarity(term, name))
+}
+
+// bigramSimilarity は文字 bigram の Dice 係数を返します
+func bigramSimilarity(a, b string) float64 {
+	ga, gb := bigrams(a), bigrams(b)
+	if len(ga) == 0 || len(gb) == 0 {
+		return 0
+	}
+	common := 0
+	for g, n := range ga {
+		common += min(n, gb[g])
+	}
+	total := 0
+	for _, n := range ga {
+		total += n
+	}
+	for _, n := range gb {
+		total += n
+	}
+	return 2 * float64(common) / float64(total)
+}
+
+// bigrams は文字列の bigram の出現数を返します。1 文字の場合はその文字を使います
+func bigrams(s string) map[string]int {
+	runes := []rune(s)
+	grams := make(map[string]int)
+	if len(runes) == 1 {
+		grams[s]++
+		return grams
+	}
+	for i := 0; i+1 < len(runes); i++ {
+		grams[string(runes[i:i+2])]++
+	}
+	return grams
+}
+
+// editSimilarity は編集距離を 0〜1 の類似度に変換して返します
+func editSimilarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 0
+	}
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return 1 - float64(prev[len(rb)])/float64(longest)
+}
+
+// candidateFromItem は format.json のオブジェクトから候補を作成します
+func candidateFromItem(category string, item map[string]interface{}) (entity.CodeCandidate, bool) {
+	name, _ := item["name"].(string)
+	code, _ := item["code"].(string)
+	if name == "" || code == "" {
+		return entity.CodeCandidate{}, false
+	}
+
+	c := entity.CodeCandidate{Category: category, Code: code, Name: name}
+	if ma, ok := item["middle_area"].(map[string]interface{}); ok {
+		c.MiddleAreaCode, _ = ma["code"].(string)
+		c.MiddleAreaName, _ = ma["name"].(string)
+		if la, ok := ma["large_area"].(map[string]interface{}); ok {
+			c.LargeAreaCode, _ = la["code"].(string)
+			c.LargeAreaName, _ = la["name"].(string)
+		}
+	}
+	if la, ok := item["large_area"].(map[string]interface{}); ok {
+		c.LargeAreaCode, _ = la["code"].(string)
+		c.LargeAreaName, _ = la["name"].(string)
+	}
+	return c, true
+}
+
+// rankCandidates は format.json の項目を語との一致度でスコア付けし、高い順に返します
+func rankCandidates(category, term string, items []map[string]interface{}) []entity.CodeCandidate {
+	normalizedTerm := normalizeName(term)
+	candidates := make([]entity.CodeCandidate, 0)
+	for _, item := range items {
+		c, ok := candidateFromItem(category, item)
+		if !ok {
+			continue
+		}
+		c.Score = scoreName(normalizedTerm, normalizeName(c.Name))
+		if c.Score >= minMatchScore {
+			candidates = append(candidates, c)
+		}
+	}
+	sortCandidates(candidates)
+	if len(candidates) > maxCandidates {
+		candidates = candidates[:maxCandidates]
+	}
+	return candidates
+}
+
+// sortCandidates はスコアの高い順に並べます。同点の場合は細かいエリアを優先します
+func sortCandidates(candidates []entity.CodeCandidate) {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Score != candidates[j].Score {
+			return candidates[i].Score > candidates[j].Score
+		}
+		return areaLevels[candidates[i].Category] < areaLevels[candidates[j].Category]
+	})
+}
+
+// closeCandidates は最上位の候補と同程度のスコアの候補を返します
+func closeCandidates(candidates []entity.CodeCandidate) []entity.CodeCandidate {
+	if len(candidates) == 0 {
+		return nil
+	}
+	top := candidates[0].Score
+	near := make([]entity.CodeCandidate, 0)
+	for _, c := range candidates {
+		if top-c.Score <= ambiguityMargin {
+			near = append(near, c)
+		}
+	}
+	return near
+}
+
+// areaLineage は候補の大区分・中区分・小区分のコードを返します
+func areaLineage(c entity.CodeCandidate) (large, middle, small string) {
+	switch c.Category {
+	case "small_area":
+		return c.LargeAreaCode, c.MiddleAreaCode, c.Code
+	case "middle_area":
+		return c.LargeAreaCode, c.Code, ""
+	case "large_area":
+		return c.Code, "", ""
+	}
+	return "", "", ""
+}
+
+// resolveArea はエリアの候補から採用するコードを決めます
+// 同程度の候補が別の大区分・中区分にまたがる場合は ambiguous を返します（コードは最上位の候補のもの）
+// 同じ中区分の中で小区分が複数ある場合は、小区分を指定せず中区分で検索します
+func resolveArea(candidates []entity.CodeCandidate) (large, middle, small string, ambiguous bool) {
+	near := closeCandidates(candidates)
+	if len(near) == 0 {
+		return "", "", "", false
+	}
+
+	larges := make(map[string]bool)
+	middles := make(map[string]bool)
+	smalls := make(map[string]bool)
+	for _, c := range near {
+		l, m, s := areaLineage(c)
+		if l != "" {
+			larges[l] = true
+		}
+		if m != "" {
+			middles[m] = true
+		}
+		if s != "" {
+			smalls[s] = true
+		}
+	}
+
+	large, middle, small = areaLineage(near[0])
+	if len(larges) > 1 || len(middles) > 1 {
+		return large, middle, small, true
+	}
+	if len(smalls) > 1 {
+		return large, middle, "", false
+	}
+	return large, middle, small, false
+}
+
+// resolveCode は候補から採用するコードを決めます。同程度の別コードがある場合は ambiguous を返します
+func resolveCode(candidates []entity.CodeCandidate) (code string, ambiguous bool) {
+	near := closeCandidates(candidates)
+	if len(near) == 0 {
+		return "", false
+	}
+	for _, c := range near[1:] {
+		if c.Code != near[0].Code {
+			return near[0].Code, true
+		}
+	}
+	return near[0].Code, false
+}
+
+// addArea はエリアの候補を解決して結果に追加します
+// 先に決まったエリアと別の大区分・中区分になる場合は、それより下位の先に決まったエリアを取り除きます
+func (r *matchReport) addArea(field, term string, candidates []entity.CodeCandidate) {
+	r.Candidates[field] = candidates
+	large, middle, small, ambiguous := resolveArea(candidates)
+	if ambiguous {
+		r.Ambiguities = append(r.Ambiguities, entity.Ambiguity{Field: field, Term: term, Candidates: closeCandidates(candidates)})
+	}
+	if large != "" {
+		if large != r.Codes["large_area"] {
+			delete(r.Codes, "middle_area")
+			delete(r.Codes, "small_area")
+		}
+		r.Codes["large_area"] = large
+	}
+	if middle != "" {
+		if middle != r.Codes["middle_area"] {
+			delete(r.Codes, "small_area")
+		}
+		r.Codes["middle_area"] = middle
+	}
+	if small != "" {
+		r.Codes["small_area"] = small
+	}
+}
+
+// addCode はジャンルや予算の候補を解決して結果に追加します
+func (r *matchReport) addCode(field, term string, candidates []entity.CodeCandidate) {
+	r.Candidates[field] = candidates
+	code, ambiguous := resolveCode(candidates)
+	if ambiguous {
+		r.Ambiguities = append(r.Ambiguities, entity.Ambiguity{Field: field, Term: term, Candidates: closeCandidates(candidates)})
+	}
+	if code != "" {
+		r.Codes[field] = code
+	}
+}
+
+// traceReport はマッピングの結果を trace に記録します
+func traceReport(trace *entity.MappingTrace, report *matchReport) {
+	if trace == nil || report == nil {
+		return
+	}
+	trace.Extracted = report.Extracted
+	trace.AppliedSynonyms = report.AppliedSynonyms
+	trace.Candidates = report.Candidates
+	trace.ChosenCodes = report.Codes
+
+	for _, syn := range report.AppliedSynonyms {
+		trace.AddStep("synonym", "同義語「%s」を適用: %s %s%s", syn.Alias, syn.Field, syn.Code, syn.Name)
+	}
+	for _, field := range sortedKeys(report.Candidates) {
+		trace.AddStep("match", "%s: 候補 %d 件", field, len(report.Candidates[field]))
+	}
+	for _, a := range report.Ambiguities {
+		trace.AddStep("ambiguity", "%s「%s」は同程度の候補が %d 件あります", a.Field, a.Term, len(a.Candidates))
+	}
+	for _, field := range sortedKeys(report.Codes) {
+		trace.AddStep("code", "%s = %s", field, report.Codes[field])
+	}
+}
+
+// sortedKeys はマップのキーを昇順で返します（記録の順序を安定させるため）
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
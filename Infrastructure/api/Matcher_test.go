@@ -0,0 +1,181 @@
+package api
+
+import (
+	"testing"
+
+	"restaurant-finder/Domain/entity"
+)
+
+// smallAreaItem は format.json の小区分の項目を作成します
+func smallAreaItem(code, name, middleCode, middleName, largeCode string) map[string]interface{} {
+	return map[string]interface{}{
+		"code": code,
+		"name": name,
+		"middle_area": map[string]interface{}{
+			"code":       middleCode,
+			"name":       middleName,
+			"large_area": map[string]interface{}{"code": largeCode, "name": "大区分" + largeCode},
+		},
+	}
+}
+
+func TestScoreNameOrdering(t *testing.T) {
+	exact := scoreName("渋谷", "渋谷")
+	prefix := scoreName("渋谷", "渋谷駅")
+	contains := scoreName("渋谷", "東渋谷")
+	similar := scoreName("しぶや", "しぶい")
+	unrelated := scoreName("渋谷", "梅田")
+
+	if exact != 1.0 {
+		t.Errorf("完全一致 = %v, want 1", exact)
+	}
+	if !(exact > prefix && prefix > contains && contains > similar && similar > unrelated) {
+		t.Errorf("スコアの順序が不正: exact=%v prefix=%v contains=%v similar=%v unrelated=%v",
+			exact, prefix, contains, similar, unrelated)
+	}
+	if unrelated >= minMatchScore {
+		t.Errorf("無関係な名前のスコア %v が候補に残ります", unrelated)
+	}
+	if scoreName("", "渋谷") != 0 || scoreName("渋谷", "") != 0 {
+		t.Error("空の名前のスコアが 0 ではありません")
+	}
+}
+
+func TestRankCandidates(t *testing.T) {
+	items := []map[string]interface{}{
+		smallAreaItem("X001", "渋谷", "Y001", "渋谷・恵比寿", "Z011"),
+		smallAreaItem("X002", "渋谷駅東口", "Y001", "渋谷・恵比寿", "Z011"),
+		smallAreaItem("X003", "梅田", "Y300", "キタ", "Z023"),
+		{"code": "", "name": "コードなし"},
+	}
+	got := rankCandidates("small_area", "ｼﾌﾞﾔ", items)
+	if len(got) != 2 {
+		t.Fatalf("候補 = %+v, want 2 件", got)
+	}
+	if got[0].Code != "X001" || got[0].Score != 1.0 {
+		t.Errorf("最上位 = %+v, want X001 (1.0)", got[0])
+	}
+	if got[0].MiddleAreaCode != "Y001" || got[0].LargeAreaCode != "Z011" {
+		t.Errorf("上位のエリアが設定されていません: %+v", got[0])
+	}
+}
+
+func TestResolveArea(t *testing.T) {
+	tests := []struct {
+		name                 string
+		candidates           []entity.CodeCandidate
+		large, middle, small string
+		ambiguous            bool
+	}{
+		{
+			name:       "候補なし",
+			candidates: nil,
+		},
+		{
+			name: "一意の小区分",
+			candidates: []entity.CodeCandidate{
+				{Category: "small_area", Code: "X001", MiddleAreaCode: "Y001", LargeAreaCode: "Z011", Score: 1},
+				{Category: "small_area", Code: "X009", MiddleAreaCode: "Y009", LargeAreaCode: "Z011", Score: 0.6},
+			},
+			large: "Z011", middle: "Y001", small: "X001",
+		},
+		{
+			name: "同じ中区分の小区分が複数",
+			candidates: []entity.CodeCandidate{
+				{Category: "small_area", Code: "X001", MiddleAreaCode: "Y001", LargeAreaCode: "Z011", Score: 0.9},
+				{Category: "small_area", Code: "X002", MiddleAreaCode: "Y001", LargeAreaCode: "Z011", Score: 0.88},
+			},
+			large: "Z011", middle: "Y001",
+		},
+		{
+			name: "別の大区分にまたがる",
+			candidates: []entity.CodeCandidate{
+				{Category: "middle_area", Code: "Y100", LargeAreaCode: "Z011", Score: 1},
+				{Category: "middle_area", Code: "Y200", LargeAreaCode: "Z023", Score: 1},
+			},
+			large: "Z011", middle: "Y100", ambiguous: true,
+		},
+	}
+	for _, tt := range tests {
+		large, middle, small, ambiguous := resolveArea(tt.candidates)
+		if large != tt.large || middle != tt.middle || small != tt.small || ambiguous != tt.ambiguous {
+			t.Errorf("%s: resolveArea() = (%q, %q, %q, %t), want (%q, %q, %q, %t)", tt.name,
+				large, middle, small, ambiguous, tt.large, tt.middle, tt.small, tt.ambiguous)
+		}
+	}
+}
+
+func TestResolveCode(t *testing.T) {
+	tests := []struct {
+		name       string
+		candidates []entity.CodeCandidate
+		code       string
+		ambiguous  bool
+	}{
+		{"候補なし", nil, "", false},
+		{"一意", []entity.CodeCandidate{{Code: "G001", Score: 1}, {Code: "G002", Score: 0.6}}, "G001", false},
+		{"同点の別コード", []entity.CodeCandidate{{Code: "G001", Score: 0.8}, {Code: "G002", Score: 0.78}}, "G001", true},
+		{"同じコードの重複", []entity.CodeCandidate{{Code: "G001", Score: 0.8}, {Code: "G001", Score: 0.8}}, "G001", false},
+	}
+	for _, tt := range tests {
+		code, ambiguous := resolveCode(tt.candidates)
+		if code != tt.code || ambiguous != tt.ambiguous {
+			t.Errorf("%s: resolveCode() = (%q, %t), want (%q, %t)", tt.name, code, ambiguous, tt.code, tt.ambiguous)
+		}
+	}
+}
+
+func TestMapResolvedParamsConflictingAreas(t *testing.T) {
+	fmtSlices := map[string][]map[string]interface{}{
+		"large_area": {
+			{"code": "Z011", "name": "東京"},
+			{"code": "Z023", "name": "大阪"},
+		},
+		"middle_area": {
+			{"code": "Y001", "name": "渋谷・恵比寿", "large_area": map[string]interface{}{"code": "Z011", "name": "東京"}},
+			{"code": "Y300", "name": "キタ", "large_area": map[string]interface{}{"code": "Z023", "name": "大阪"}},
+		},
+		"small_area": {
+			smallAreaItem("X001", "渋谷", "Y001", "渋谷・恵比寿", "Z011"),
+			smallAreaItem("X300", "梅田", "Y300", "キタ", "Z023"),
+		},
+	}
+	tests := []struct {
+		name                 string
+		params               map[string]string
+		large, middle, small string
+	}{
+		{
+			name:   "地名だけ",
+			params: map[string]string{"location": "渋谷"},
+			large:  "Z011", middle: "Y001", small: "X001",
+		},
+		{
+			name:   "同じ大区分を個別に指定",
+			params: map[string]string{"location": "渋谷", "large_area": "東京"},
+			large:  "Z011", middle: "Y001", small: "X001",
+		},
+		{
+			name:   "別の大区分を指定すると地名の中区分・小区分を除く",
+			params: map[string]string{"location": "渋谷", "large_area": "大阪"},
+			large:  "Z023",
+		},
+		{
+			name:   "別の中区分を指定すると地名の小区分を除く",
+			params: map[string]string{"location": "渋谷", "middle_area": "キタ"},
+			large:  "Z023", middle: "Y300",
+		},
+		{
+			name:   "別の小区分を指定すると上位のエリアも揃える",
+			params: map[string]string{"location": "渋谷", "small_area": "梅田"},
+			large:  "Z023", middle: "Y300", small: "X300",
+		},
+	}
+	for _, tt := range tests {
+		report := mapResolvedParams(tt.params, nil, nil, fmtSlices)
+		large, middle, small := report.Codes["large_area"], report.Codes["middle_area"], report.Codes["small_area"]
+		if large != tt.large || middle != tt.middle || small != tt.small {
+			t.Errorf("%s: areas = (%q, %q, %q), want (%q, %q, %q)", tt.name, large, middle, small, tt.large, tt.middle, tt.small)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// defaultModelTimeout は 1 回のモデル呼び出しの既定のタイムアウトです
+const defaultModelTimeout = 20 * time.Second
+
+// ModelConfig は用途ごとに使うモデルと、その優先順位（フォールバックチェーン）です
+type ModelConfig struct {
+	// Extraction は検索パラメータ抽出に使うモデルを優先順に並べたものです
+	Extraction []string
+	// Summary は検索結果の説明に使うモデルを優先順に並べたものです
+	Summary []string
+	// Timeout は 1 回のモデル呼び出しのタイムアウトです
+	Timeout time.Duration
+}
+
+// DefaultModelConfig は既定のモデル構成を返します
+func DefaultModelConfig() ModelConfig {
+	return ModelConfig{
+		Extraction: []string{openai.GPT4o, openai.GPT4oMini},
+		Summary:    []string{openai.GPT4o, openai.GPT4oMini},
+		Timeout:    defaultModelTimeout,
+	}
+}
+
+// LoadModelConfigFromEnv は環境変数からモデル構成を読み込みます
+// OPENAI_EXTRACTION_MODELS / OPENAI_SUMMARY_MODELS はカンマ区切りのモデル名、
+// OPENAI_TIMEOUT は "15s" のような時間で指定します
+func LoadModelConfigFromEnv() ModelConfig {
+	cfg := DefaultModelConfig()
+	if models := splitModels(os.Getenv("OPENAI_EXTRACTION_MODELS")); len(models) > 0 {
+		cfg.Extraction = models
+	}
+	if models := splitModels(os.Getenv("OPENAI_SUMMARY_MODELS")); len(models) > 0 {
+		cfg.Summary = models
+	}
+	if v := os.Getenv("OPENAI_TIMEOUT"); v != "" {
+		timeout, err := time.ParseDuration(v)
+		if err != nil {
+			fmt.Printf("警告: OPENAI_TIMEOUT が不正です: %v\n", err)
+		} else {
+			cfg.Timeout = timeout
+		}
+	}
+	return cfg
+}
+
+// splitModels はカンマ区切りのモデル名をスライスに変換します
+func splitModels(s string) []string {
+	models := make([]string, 0)
+	for _, m := range strings.Split(s, ",") {
+		if m = strings.TrimSpace(m); m != "" {
+			models = append(models, m)
+		}
+	}
+	return models
+}
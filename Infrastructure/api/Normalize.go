@@ -0,0 +1,172 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// nameSuffixes は比較時に末尾から取り除く行政区分の接尾辞です
+var nameSuffixes = []string{"市", "区", "都", "府", "県", "道"}
+
+// suffixedNames は接尾辞を取り除かない名前です（"北海道" を "北海" にしないため）
+var suffixedNames = map[string]bool{
+	"北海道": true,
+}
+
+// defaultReadings は地名・ジャンルの読み（ひらがな）から表記への組み込みの索引です
+// readings.json があれば読み込んで追加します
+var defaultReadings = map[string]string{
+	"とうきょう":    "東京",
+	"しんじゅく":    "新宿",
+	"しぶや":      "渋谷",
+	"いけぶくろ":    "池袋",
+	"ぎんざ":      "銀座",
+	"ろっぽんぎ":    "六本木",
+	"あきはばら":    "秋葉原",
+	"うえの":      "上野",
+	"あさくさ":     "浅草",
+	"しながわ":     "品川",
+	"えびす":      "恵比寿",
+	"おもてさんどう":  "表参道",
+	"はらじゅく":    "原宿",
+	"きちじょうじ":   "吉祥寺",
+	"しもきたざわ":   "下北沢",
+	"なかめぐろ":    "中目黒",
+	"じゆうがおか":   "自由が丘",
+	"たかだのばば":   "高田馬場",
+	"ゆうらくちょう":  "有楽町",
+	"しんばし":     "新橋",
+	"かんだ":      "神田",
+	"きんしちょう":   "錦糸町",
+	"あかばね":     "赤羽",
+	"きたせんじゅ":   "北千住",
+	"よこはま":     "横浜",
+	"かわさき":     "川崎",
+	"おおみや":     "大宮",
+	"おおさか":     "大阪",
+	"うめだ":      "梅田",
+	"なんば":      "難波",
+	"てんのうじ":    "天王寺",
+	"きょうと":     "京都",
+	"こうべ":      "神戸",
+	"さんのみや":    "三宮",
+	"なごや":      "名古屋",
+	"さかえ":      "栄",
+	"ふくおか":     "福岡",
+	"はかた":      "博多",
+	"てんじん":     "天神",
+	"さっぽろ":     "札幌",
+	"せんだい":     "仙台",
+	"いざかや":     "居酒屋",
+	"やきにく":     "焼肉",
+	"すし":       "寿司",
+	"わしょく":     "和食",
+	"ようしょく":    "洋食",
+	"ちゅうか":     "中華",
+	"かんこくりょうり": "韓国料理",
+	"てんぷら":     "天ぷら",
+	"やきとり":     "焼き鳥",
+	"おこのみやき":   "お好み焼き",
+}
+
+var (
+	readingIndex     map[string]string
+	readingIndexOnce sync.Once
+)
+
+// loadReadingIndex は読みの索引を作成します。キーは正規化済みの読みです
+func loadReadingIndex() map[string]string {
+	readingIndexOnce.Do(func() {
+		readings := make(map[string]string, len(defaultReadings))
+		for k, v := range defaultReadings {
+			readings[k] = v
+		}
+
+		for _, path := range []string{"readings.json", "../readings.json", "../../readings.json"} {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				continue
+			}
+			var extra map[string]string
+			if err := json.Unmarshal(data, &extra); err != nil {
+				fmt.Printf("警告: %s を読み込めません: %v\n", path, err)
+				break
+			}
+			for k, v := range extra {
+				readings[k] = v
+			}
+			fmt.Printf("readings.json を読み込みました: %s\n", path)
+			break
+		}
+
+		readingIndex = make(map[string]string, len(readings))
+		for reading, name := range readings {
+			readingIndex[normalizeText(reading)] = name
+		}
+	})
+	return readingIndex
+}
+
+// normalizeName は名前を比較用に正規化します
+// 正規化したうえで読み（ひらがな）に一致する場合は表記に置き換えて正規化します
+func normalizeName(s string) string {
+	normalized := normalizeText(unifyPlaceGa(s))
+	if name, ok := loadReadingIndex()[normalized]; ok {
+		normalized = normalizeText(unifyPlaceGa(name))
+	}
+	return stripNameSuffix(normalized)
+}
+
+// unifyPlaceGa は地名の "ヶ" と "ケ" を "が" に統一します（"自由ヶ丘" と "自由が丘" を同じ名前として比較するため）
+// "ケ" は "ケーキ" のような語もあるため、前後が漢字の場合だけ置き換えます
+func unifyPlaceGa(s string) string {
+	runes := []rune(norm.NFKC.String(s))
+	for i, r := range runes {
+		switch {
+		case r == 'ヶ' || r == 'ゖ':
+			runes[i] = 'が'
+		case r == 'ケ' && i > 0 && i+1 < len(runes) && unicode.Is(unicode.Han, runes[i-1]) && unicode.Is(unicode.Han, runes[i+1]):
+			runes[i] = 'が'
+		}
+	}
+	return string(runes)
+}
+
+// normalizeText は全角・半角の統一（NFKC）、小文字化、カタカナのひらがな化、長音記号と空白の除去を行います
+func normalizeText(s string) string {
+	s = norm.NFKC.String(s)
+	s = strings.ToLower(s)
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == 'ー':
+			return -1
+		case r == ' ' || r == '\t':
+			return -1
+		case r >= 'ァ' && r <= 'ヶ':
+			// カタカナをひらがなに統一する（ヵ・ヶ は ゕ・ゖ に対応）
+			return r - ('ァ' - 'ぁ')
+		}
+		return r
+	}, s)
+}
+
+// stripNameSuffix は末尾の行政区分の接尾辞を取り除きます
+// "京都" のように取り除くと 1 文字になる場合と、"北海道" はそのまま残します
+func stripNameSuffix(s string) string {
+	if suffixedNames[s] {
+		return s
+	}
+	for _, suffix := range nameSuffixes {
+		if strings.HasSuffix(s, suffix) && utf8.RuneCountInString(s) > 2 {
+			return strings.TrimSuffix(s, suffix)
+		}
+	}
+	return s
+}
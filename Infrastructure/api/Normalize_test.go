@@ -0,0 +1,53 @@
+package api
+
+import "testing"
+
+func TestNormalizeText(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"ｼﾌﾞﾔ", "しぶや"},
+		{"シブヤ", "しぶや"},
+		{"ＳＨＩＢＵＹＡ　ラーメン", "shibuyaらめん"},
+		{"焼き鳥 食べ放題", "焼き鳥食べ放題"},
+		{"ヴェトナム", "ゔぇとなむ"},
+		{"ヶ丘", "ゖ丘"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := normalizeText(tt.in); got != tt.want {
+			t.Errorf("normalizeText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"渋谷", "渋谷"},
+		{"渋谷区", "渋谷"},
+		{"しぶや", "渋谷"},
+		{"ｼﾌﾞﾔ", "渋谷"},
+		{"シンジュク", "新宿"},
+		{"東京都", "東京"},
+		{"京都", "京都"},
+		{"大阪府", "大阪"},
+		{"イザカヤ", "居酒屋"},
+		{"さっぽろ", "札幌"},
+		{"北海道", "北海道"},
+		{"自由ヶ丘", "自由が丘"},
+		{"自由ケ丘", "自由が丘"},
+		{"自由が丘", "自由が丘"},
+		{"じゆうがおか", "自由が丘"},
+		{"霞ヶ関", "霞が関"},
+		{"ケーキ", "けき"},
+	}
+	for _, tt := range tests {
+		if got := normalizeName(tt.in); got != tt.want {
+			t.Errorf("normalizeName(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
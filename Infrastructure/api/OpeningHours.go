@@ -0,0 +1,222 @@
+package api
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+	"restaurant-finder/Domain/entity"
+)
+
+// dayToken は曜日・祝日の表記 1 つに一致する正規表現です
+const dayToken = `(?:祝前日|祝後日|祝日|祝|[月火水木金土日])(?:曜日|曜)?`
+
+// weekdayNames は曜日の 1 文字表記と time.Weekday の対応です
+var weekdayNames = map[string]time.Weekday{
+	"日": time.Sunday,
+	"月": time.Monday,
+	"火": time.Tuesday,
+	"水": time.Wednesday,
+	"木": time.Thursday,
+	"金": time.Friday,
+	"土": time.Saturday,
+}
+
+var (
+	// parenthesisPattern は L.O. などの補足（括弧内）です
+	parenthesisPattern = regexp.MustCompile(`\([^)]*\)`)
+	// daySpecPattern は "月~金、祝前日:" のような曜日の指定です
+	daySpecPattern = regexp.MustCompile(dayToken + `(?:\s*[~、,・/]\s*` + dayToken + `)*\s*:`)
+	// timeRangePattern は "17:00~翌2:00" のような営業時間帯です
+	timeRangePattern = regexp.MustCompile(`(翌)?(\d{1,2}):(\d{2})\s*~\s*(翌)?(\d{1,2}):(\d{2})`)
+	// nthWeekdayPattern は "第2水曜" のような月単位の休みです
+	nthWeekdayPattern = regexp.MustCompile(`第\d[第\d、,・]*[月火水木金土日](?:曜日|曜)?`)
+	// weekdayRangePattern は定休日の "月~水" のような曜日の範囲です
+	weekdayRangePattern = regexp.MustCompile(`[月火水木金土日]\s*~\s*[月火水木金土日]`)
+	// closeDatePattern は定休日の "12月31日" や "1/1" のような日付です（曜日の字を含むため先に取り除く）
+	closeDatePattern = regexp.MustCompile(`(?:\d+年)?\d+月\d+日|\d+/\d+|\d+日`)
+)
+
+// closeNonWeekdayWords は定休日の文字列のうち、曜日の字を含むが曜日ではない語を取り除きます
+var closeNonWeekdayWords = strings.NewReplacer(
+	"祝前日", " ", "祝後日", " ", "祝日", " ", "定休日", " ", "年末年始", " ", "休日", " ", "平日", " ",
+	"元日", " ", "毎日", " ", "当日", " ", "前日", " ", "翌日", " ", "毎月", " ", "月末", " ", "月初", " ",
+)
+
+// normalizeHoursText は全角・半角と波ダッシュの表記を統一し、括弧内の補足を取り除きます
+func normalizeHoursText(s string) string {
+	s = norm.NFKC.String(s)
+	// NFKC で全角のハイフン・チルダは ASCII になるため、"11:00-23:00" の "-" も範囲の区切りとして扱う
+	s = strings.NewReplacer("〜", "~", "-", "~", "−", "~", "―", "~", "：", ":").Replace(s)
+	return parenthesisPattern.ReplaceAllString(s, " ")
+}
+
+// ParseOpeningHours は HotPepper の営業時間（open）と定休日（close）の文字列を解析します
+// 例: "月~金: 17:00~翌2:00 土、日、祝日: 11:30~23:00" / "日、祝日"
+func ParseOpeningHours(open, close string) *entity.OpeningHours {
+	hours := &entity.OpeningHours{}
+	parseOpenText(hours, normalizeHoursText(open))
+	parseCloseText(hours, normalizeHoursText(close))
+	return hours
+}
+
+// parseOpenText は営業時間の文字列を曜日ごとの営業時間帯に変換します
+func parseOpenText(hours *entity.OpeningHours, text string) {
+	specs := daySpecPattern.FindAllStringIndex(text, -1)
+	if len(specs) == 0 {
+		// 曜日の指定がない場合は毎日同じ営業時間とみなす
+		ranges := parseTimeRanges(text)
+		for day := range hours.Weekly {
+			hours.Weekly[day] = append(hours.Weekly[day], ranges...)
+		}
+		hours.Parsed = len(ranges) > 0
+		return
+	}
+
+	for i, spec := range specs {
+		end := len(text)
+		if i+1 < len(specs) {
+			end = specs[i+1][0]
+		}
+		ranges := parseTimeRanges(text[spec[1]:end])
+		if len(ranges) == 0 {
+			continue
+		}
+		hours.Parsed = true
+
+		days, holiday, holidayEve := parseDaySpec(text[spec[0] : spec[1]-1])
+		for _, day := range days {
+			hours.Weekly[day] = append(hours.Weekly[day], ranges...)
+		}
+		if holiday {
+			hours.Holiday = append(hours.Holiday, ranges...)
+			hours.HasHoliday = true
+		}
+		if holidayEve {
+			hours.HolidayEve = append(hours.HolidayEve, ranges...)
+			hours.HasHolidayEve = true
+		}
+	}
+}
+
+// parseTimeRanges は文字列に含まれる営業時間帯をすべて取り出します
+func parseTimeRanges(text string) []entity.TimeRange {
+	ranges := make([]entity.TimeRange, 0)
+	for _, m := range timeRangePattern.FindAllStringSubmatch(text, -1) {
+		start := clockMinutes(m[2], m[3])
+		end := clockMinutes(m[5], m[6])
+		if m[1] != "" {
+			start += entity.MinutesPerDay
+		}
+		if m[4] != "" || end <= start {
+			// "翌2:00" や "17:00~2:00" は日付をまたぐ
+			end += entity.MinutesPerDay
+		}
+		if end > start {
+			ranges = append(ranges, entity.TimeRange{Start: start, End: end})
+		}
+	}
+	return ranges
+}
+
+// clockMinutes は時・分を 0:00 からの分に変換します
+func clockMinutes(hour, minute string) int {
+	h, _ := strconv.Atoi(hour)
+	m, _ := strconv.Atoi(minute)
+	return h*60 + m
+}
+
+// parseDaySpec は "月~金、祝前日" のような曜日の指定を曜日の一覧と祝日・祝前日の指定に変換します
+func parseDaySpec(spec string) (days []time.Weekday, holiday, holidayEve bool) {
+	spec = strings.NewReplacer("曜日", "", "曜", "").Replace(spec)
+	for _, part := range strings.FieldsFunc(spec, func(r rune) bool {
+		return r == '、' || r == ',' || r == '・' || r == '/' || r == ' '
+	}) {
+		switch part {
+		case "祝日", "祝":
+			holiday = true
+			continue
+		case "祝前日":
+			holidayEve = true
+			continue
+		case "祝後日":
+			continue
+		}
+		if from, to, ok := strings.Cut(part, "~"); ok {
+			start, okStart := weekdayNames[strings.TrimSpace(from)]
+			end, okEnd := weekdayNames[strings.TrimSpace(to)]
+			if !okStart || !okEnd {
+				continue
+			}
+			// "金~月" のように週をまたぐ範囲も扱う
+			for d := start; ; d = (d + 1) % 7 {
+				days = append(days, d)
+				if d == end {
+					break
+				}
+			}
+			continue
+		}
+		if d, ok := weekdayNames[part]; ok {
+			days = append(days, d)
+		}
+	}
+	return days, holiday, holidayEve
+}
+
+// parseCloseText は定休日の文字列を解析します
+// "第2水曜" のように月単位の休みは曜日の定休日として扱わず、不定休とします
+func parseCloseText(hours *entity.OpeningHours, text string) {
+	text = strings.TrimSpace(text)
+	if text == "" || strings.Contains(text, "なし") || strings.Contains(text, "無休") {
+		return
+	}
+	if strings.Contains(text, "不定休") || nthWeekdayPattern.MatchString(text) {
+		hours.Irregular = true
+		text = nthWeekdayPattern.ReplaceAllString(text, " ")
+	}
+	if strings.Contains(strings.ReplaceAll(text, "祝前日", ""), "祝") {
+		hours.ClosedOnHolidays = true
+	}
+
+	// 日付と、曜日以外で曜日の字を含む語（"平日"・"元日" など）を取り除いてから曜日を探す
+	text = closeDatePattern.ReplaceAllString(text, " ")
+	text = closeNonWeekdayWords.Replace(text)
+	for _, day := range weekdayTokens(text) {
+		hours.ClosedWeekdays[day] = true
+	}
+	for _, spec := range weekdayRangePattern.FindAllString(text, -1) {
+		days, _, _ := parseDaySpec(spec)
+		for _, day := range days {
+			hours.ClosedWeekdays[day] = true
+		}
+	}
+}
+
+// weekdayTokens は単独で書かれた曜日（"月"・"月曜"・"月曜日"）を返します
+// 数字と隣り合う字（"3日" や "1月"）は日付の一部のため曜日とみなしません
+func weekdayTokens(text string) []time.Weekday {
+	runes := []rune(text)
+	days := make([]time.Weekday, 0)
+	for i := 0; i < len(runes); i++ {
+		day, ok := weekdayNames[string(runes[i])]
+		if !ok {
+			continue
+		}
+		if i > 0 && unicode.IsDigit(runes[i-1]) || i+1 < len(runes) && unicode.IsDigit(runes[i+1]) {
+			continue
+		}
+		days = append(days, day)
+		// "曜日" の "日" を日曜日と読まないよう読み飛ばす
+		if i+1 < len(runes) && runes[i+1] == '曜' {
+			i++
+			if i+1 < len(runes) && runes[i+1] == '日' {
+				i++
+			}
+		}
+	}
+	return days
+}
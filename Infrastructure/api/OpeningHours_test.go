@@ -0,0 +1,164 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"restaurant-finder/Domain/entity"
+)
+
+func TestParseOpeningHoursOpenText(t *testing.T) {
+	tests := []struct {
+		name string
+		open string
+		// want は曜日ごとの営業時間帯（添字は time.Weekday）
+		want    [7][]entity.TimeRange
+		holiday []entity.TimeRange
+	}{
+		{
+			name: "日付をまたぐ営業と祝日",
+			open: "月~金: 17:00~翌2:00 土、日、祝日: 11:30~23:00",
+			want: [7][]entity.TimeRange{
+				{{Start: 690, End: 1380}},
+				{{Start: 1020, End: 1560}}, {{Start: 1020, End: 1560}}, {{Start: 1020, End: 1560}},
+				{{Start: 1020, End: 1560}}, {{Start: 1020, End: 1560}},
+				{{Start: 690, End: 1380}},
+			},
+			holiday: []entity.TimeRange{{Start: 690, End: 1380}},
+		},
+		{
+			name: "翌のない日付またぎ",
+			open: "18:00~3:00",
+			want: everyDay(entity.TimeRange{Start: 1080, End: 1620}),
+		},
+		{
+			name: "ASCII のハイフン",
+			open: "11:00-23:00",
+			want: everyDay(entity.TimeRange{Start: 660, End: 1380}),
+		},
+		{
+			name: "全角の表記と L.O.",
+			open: "１１：００－１５：００（L.O.14：30） １７：００〜２２：００",
+			want: everyDay(entity.TimeRange{Start: 660, End: 900}, entity.TimeRange{Start: 1020, End: 1320}),
+		},
+		{
+			name: "週をまたぐ曜日の範囲",
+			open: "金~日: 18:00~翌1:00",
+			want: [7][]entity.TimeRange{
+				{{Start: 1080, End: 1500}}, nil, nil, nil, nil,
+				{{Start: 1080, End: 1500}}, {{Start: 1080, End: 1500}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		hours := ParseOpeningHours(tt.open, "")
+		if !hours.Parsed {
+			t.Errorf("%s: 営業時間を解析できません: %q", tt.name, tt.open)
+			continue
+		}
+		for day := range hours.Weekly {
+			if len(hours.Weekly[day]) == 0 && len(tt.want[day]) == 0 {
+				continue
+			}
+			if !reflect.DeepEqual(hours.Weekly[day], tt.want[day]) {
+				t.Errorf("%s: %v = %v, want %v", tt.name, time.Weekday(day), hours.Weekly[day], tt.want[day])
+			}
+		}
+		if !reflect.DeepEqual(hours.Holiday, tt.holiday) && (len(hours.Holiday) != 0 || len(tt.holiday) != 0) {
+			t.Errorf("%s: 祝日 = %v, want %v", tt.name, hours.Holiday, tt.holiday)
+		}
+	}
+}
+
+func TestParseOpeningHoursCloseText(t *testing.T) {
+	tests := []struct {
+		close     string
+		closed    []time.Weekday
+		holidays  bool
+		irregular bool
+	}{
+		{close: "毎週月曜日", closed: []time.Weekday{time.Monday}},
+		{close: "月曜・火曜", closed: []time.Weekday{time.Monday, time.Tuesday}},
+		{close: "月~水", closed: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday}},
+		{close: "日、祝日", closed: []time.Weekday{time.Sunday}, holidays: true},
+		{close: "土日祝", closed: []time.Weekday{time.Saturday, time.Sunday}, holidays: true},
+		{close: "水曜日(祝日の場合は翌日)", closed: []time.Weekday{time.Wednesday}},
+		{close: "定休日: 木曜日", closed: []time.Weekday{time.Thursday}},
+		{close: "12月31日～1月3日"},
+		{close: "毎週月曜日 元日", closed: []time.Weekday{time.Monday}},
+		{close: "平日"},
+		{close: "毎月15日"},
+		{close: "年末年始"},
+		{close: "第2水曜日", irregular: true},
+		{close: "第1・第3月曜、日曜", closed: []time.Weekday{time.Sunday}, irregular: true},
+		{close: "不定休", irregular: true},
+		{close: "なし"},
+		{close: ""},
+	}
+	for _, tt := range tests {
+		hours := ParseOpeningHours("11:00~22:00", tt.close)
+		var want [7]bool
+		for _, day := range tt.closed {
+			want[day] = true
+		}
+		if hours.ClosedWeekdays != want {
+			t.Errorf("%q: ClosedWeekdays = %v, want %v", tt.close, hours.ClosedWeekdays, want)
+		}
+		if hours.ClosedOnHolidays != tt.holidays {
+			t.Errorf("%q: ClosedOnHolidays = %t, want %t", tt.close, hours.ClosedOnHolidays, tt.holidays)
+		}
+		if hours.Irregular != tt.irregular {
+			t.Errorf("%q: Irregular = %t, want %t", tt.close, hours.Irregular, tt.irregular)
+		}
+	}
+}
+
+func TestIsOpenAt(t *testing.T) {
+	at := func(s string) time.Time {
+		t.Helper()
+		v, err := time.ParseInLocation("2006-01-02 15:04", s, entity.JST)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	tests := []struct {
+		name        string
+		open, close string
+		at          string
+		wantOpen    bool
+		wantKnown   bool
+	}{
+		{"平日の夜", "月~金: 17:00~翌2:00 土、日、祝日: 11:30~23:00", "", "2025-06-02 23:00", true, true},
+		{"月曜の深夜（火曜 1:30）", "月~金: 17:00~翌2:00 土、日、祝日: 11:30~23:00", "", "2025-06-03 01:30", true, true},
+		{"閉店後（火曜 2:30）", "月~金: 17:00~翌2:00 土、日、祝日: 11:30~23:00", "", "2025-06-03 02:30", false, true},
+		{"金曜の深夜（土曜 1:00）", "月~金: 17:00~翌2:00 土、日、祝日: 11:30~23:00", "", "2025-06-07 01:00", true, true},
+		{"祝日の昼（海の日）", "月~金: 17:00~翌2:00 土、日、祝日: 11:30~23:00", "", "2025-07-21 12:00", true, true},
+		{"平日の昼", "月~金: 17:00~翌2:00 土、日、祝日: 11:30~23:00", "", "2025-06-02 12:00", false, true},
+		{"定休日", "11:00-22:00", "月曜日", "2025-06-02 12:00", false, true},
+		{"定休日が祝日なら営業", "11:00-22:00", "月曜日", "2025-07-21 12:00", true, true},
+		{"祝日休み", "11:00-22:00", "日、祝日", "2025-07-21 12:00", false, true},
+		{"日付の休みで曜日は休みにしない", "11:00-22:00", "12月31日～1月3日", "2025-06-01 12:00", true, true},
+		{"第2水曜は休みと断定しない", "11:00-22:00", "第2水曜日", "2025-06-11 12:00", true, true},
+		{"解析できない営業時間", "要問い合わせ", "", "2025-06-02 12:00", false, false},
+	}
+	for _, tt := range tests {
+		open, known := ParseOpeningHours(tt.open, tt.close).IsOpenAt(at(tt.at))
+		if open != tt.wantOpen || known != tt.wantKnown {
+			t.Errorf("%s: IsOpenAt() = (%t, %t), want (%t, %t)", tt.name, open, known, tt.wantOpen, tt.wantKnown)
+		}
+	}
+	if open, known := (*entity.OpeningHours)(nil).IsOpenAt(at("2025-06-02 12:00")); open || known {
+		t.Error("nil の営業時間で営業中と判定されました")
+	}
+}
+
+// everyDay は毎日同じ営業時間帯を返します
+func everyDay(ranges ...entity.TimeRange) [7][]entity.TimeRange {
+	var week [7][]entity.TimeRange
+	for day := range week {
+		week[day] = ranges
+	}
+	return week
+}
@@ -0,0 +1,279 @@
+package api
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"restaurant-finder/Domain/entity"
+)
+
+// extractionPromptVersion は抽出プロンプトのバージョンです
+// プロンプトや保存する解釈結果の形式を変更したら更新し、古いキャッシュを使わないようにします
+const extractionPromptVersion = "v6"
+
+// defaultQueryCacheTTL はキャッシュの既定の有効期間です
+const defaultQueryCacheTTL = 24 * time.Hour
+
+// defaultQueryCacheSize はメモリキャッシュに保存する既定の最大件数です
+const defaultQueryCacheSize = 1000
+
+// QueryCache は解決済みの解釈結果（検索パラメータとマッチングの候補）を保存するキャッシュのインターフェイスです
+type QueryCache interface {
+	Get(key string) (*entity.QueryInterpretation, bool)
+	Set(key string, interpretation *entity.QueryInterpretation)
+}
+
+// queryCacheEntry はキャッシュの 1 件分です
+type queryCacheEntry struct {
+	Interpretation entity.QueryInterpretation `json:"interpretation"`
+	ExpiresAt      time.Time                  `json:"expires_at"`
+}
+
+// cachedCopy はキャッシュに保存・取得する解釈結果のコピーを作成します
+// 呼び出し側でパラメータを書き換えてもキャッシュに影響しないようにします
+func cachedCopy(interpretation *entity.QueryInterpretation) *entity.QueryInterpretation {
+	copied := *interpretation
+	if interpretation.Params != nil {
+		copied.Params = interpretation.Params.Clone()
+	}
+	copied.Usage = entity.TokenUsage{}
+	copied.Trace = interpretation.Trace.Clone()
+	return &copied
+}
+
+// queryCacheKey は正規化したプロンプトとプロンプトのバージョンからキャッシュキーを作成します
+// 同義語辞書の編集や抽出に使うモデルの変更の後に古い解決結果を使わないよう、辞書の版とモデルの一覧もキーに含めます
+func queryCacheKey(prompt, synonymRevision string, models []string) string {
+	normalized := strings.Join(strings.Fields(strings.ToLower(prompt)), " ")
+	return extractionPromptVersion + ":" + strings.Join(models, ",") + ":" + synonymRevision + ":" + normalized
+}
+
+// MemoryQueryCache はメモリ上に保存するキャッシュです
+// 最大件数を超えた場合は最も長く使われていないものから削除し、期限切れのものは保存のたびに取り除きます
+type MemoryQueryCache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	// order は最近使った順のキーの一覧です（先頭が最新）
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+// memoryQueryCacheItem はメモリキャッシュの 1 件分とそのキーです
+type memoryQueryCacheItem struct {
+	key   string
+	entry queryCacheEntry
+}
+
+// NewMemoryQueryCache は最大 maxEntries 件を保存する MemoryQueryCache を作成します（0 以下の場合は既定の件数）
+func NewMemoryQueryCache(ttl time.Duration, maxEntries int) *MemoryQueryCache {
+	if maxEntries <= 0 {
+		maxEntries = defaultQueryCacheSize
+	}
+	return &MemoryQueryCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+// Get はキャッシュから解釈結果を取得します
+func (c *MemoryQueryCache) Get(key string) (*entity.QueryInterpretation, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	item := element.Value.(*memoryQueryCacheItem)
+	if time.Now().After(item.entry.ExpiresAt) {
+		c.remove(element)
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return cachedCopy(&item.entry.Interpretation), true
+}
+
+// Set は解釈結果をキャッシュに保存します
+func (c *MemoryQueryCache) Set(key string, interpretation *entity.QueryInterpretation) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	entry := queryCacheEntry{Interpretation: *cachedCopy(interpretation), ExpiresAt: now.Add(c.ttl)}
+	if element, ok := c.entries[key]; ok {
+		element.Value.(*memoryQueryCacheItem).entry = entry
+		c.order.MoveToFront(element)
+	} else {
+		c.entries[key] = c.order.PushFront(&memoryQueryCacheItem{key: key, entry: entry})
+	}
+
+	// 最大件数を超えた分と、最も長く使われていないものから続く期限切れのものを取り除く
+	for element := c.order.Back(); element != nil; element = c.order.Back() {
+		item := element.Value.(*memoryQueryCacheItem)
+		if c.order.Len() <= c.maxEntries && !now.After(item.entry.ExpiresAt) {
+			break
+		}
+		c.remove(element)
+	}
+}
+
+// Len は保存されている件数を返します
+func (c *MemoryQueryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// remove はキャッシュから 1 件を削除します
+func (c *MemoryQueryCache) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*memoryQueryCacheItem).key)
+}
+
+// FileQueryCache はディスク上の JSON ファイルに保存するキャッシュです
+// 期限切れのファイルは読み込み時と保存時に削除し、最大件数を超えた場合は古く保存したものから削除します
+type FileQueryCache struct {
+	mu         sync.Mutex
+	dir        string
+	ttl        time.Duration
+	maxEntries int
+}
+
+// NewFileQueryCache は最大 maxEntries 件を保存する FileQueryCache を作成します（0 以下の場合は既定の件数）
+func NewFileQueryCache(dir string, ttl time.Duration, maxEntries int) (*FileQueryCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("キャッシュディレクトリを作成できません: %w", err)
+	}
+	if maxEntries <= 0 {
+		maxEntries = defaultQueryCacheSize
+	}
+	cache := &FileQueryCache{dir: dir, ttl: ttl, maxEntries: maxEntries}
+	cache.prune(time.Now())
+	return cache, nil
+}
+
+// path はキーに対応するファイルパスを返します
+func (c *FileQueryCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// Get はキャッシュから解釈結果を取得します
+func (c *FileQueryCache) Get(key string) (*entity.QueryInterpretation, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	path := c.path(key)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var entry queryCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Interpretation.Params == nil || time.Now().After(entry.ExpiresAt) {
+		// 期限切れや読めないファイルは残しておいても使わないため削除する
+		os.Remove(path)
+		return nil, false
+	}
+	return &entry.Interpretation, true
+}
+
+// Set は解釈結果をキャッシュに保存します
+func (c *FileQueryCache) Set(key string, interpretation *entity.QueryInterpretation) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, err := json.Marshal(queryCacheEntry{Interpretation: *cachedCopy(interpretation), ExpiresAt: time.Now().Add(c.ttl)})
+	if err != nil {
+		fmt.Printf("警告: キャッシュの保存に失敗しました: %v\n", err)
+		return
+	}
+	if err := os.WriteFile(c.path(key), data, 0o644); err != nil {
+		fmt.Printf("警告: キャッシュの保存に失敗しました: %v\n", err)
+		return
+	}
+	c.prune(time.Now())
+}
+
+// prune は期限切れのファイルと、最大件数を超えた分の古く保存したファイルを削除します
+// 保存した時刻はファイルの更新時刻で判定します（有効期限は保存した時刻から ttl 後です）
+func (c *FileQueryCache) prune(now time.Time) {
+	dirEntries, err := os.ReadDir(c.dir)
+	if err != nil {
+		fmt.Printf("警告: キャッシュディレクトリを読み込めません: %v\n", err)
+		return
+	}
+	type cacheFile struct {
+		path    string
+		modTime time.Time
+	}
+	files := make([]cacheFile, 0, len(dirEntries))
+	for _, dirEntry := range dirEntries {
+		if dirEntry.IsDir() || filepath.Ext(dirEntry.Name()) != ".json" {
+			continue
+		}
+		info, err := dirEntry.Info()
+		if err != nil {
+			continue
+		}
+		path := filepath.Join(c.dir, dirEntry.Name())
+		if now.After(info.ModTime().Add(c.ttl)) {
+			os.Remove(path)
+			continue
+		}
+		files = append(files, cacheFile{path: path, modTime: info.ModTime()})
+	}
+	if len(files) <= c.maxEntries {
+		return
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+	for _, file := range files[:len(files)-c.maxEntries] {
+		os.Remove(file.path)
+	}
+}
+
+// NewQueryCacheFromEnv は環境変数の設定でキャッシュを作成します
+// QUERY_CACHE_DIR が設定されていればディスク、なければメモリに保存します
+// 有効期間は QUERY_CACHE_TTL（例: "12h"）、保存する最大件数は QUERY_CACHE_SIZE で変更できます
+func NewQueryCacheFromEnv() QueryCache {
+	ttl := defaultQueryCacheTTL
+	if v := os.Getenv("QUERY_CACHE_TTL"); v != "" {
+		parsed, err := time.ParseDuration(v)
+		if err != nil {
+			fmt.Printf("警告: QUERY_CACHE_TTL が不正です: %v\n", err)
+		} else {
+			ttl = parsed
+		}
+	}
+
+	size := defaultQueryCacheSize
+	if v := os.Getenv("QUERY_CACHE_SIZE"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed <= 0 {
+			fmt.Printf("警告: QUERY_CACHE_SIZE が不正です: %q\n", v)
+		} else {
+			size = parsed
+		}
+	}
+
+	if dir := os.Getenv("QUERY_CACHE_DIR"); dir != "" {
+		fileCache, err := NewFileQueryCache(dir, ttl, size)
+		if err == nil {
+			return fileCache
+		}
+		fmt.Printf("警告: %v; メモリキャッシュを使用します\n", err)
+	}
+	return NewMemoryQueryCache(ttl, size)
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"restaurant-finder/Domain/entity"
+)
+
+func TestMemoryQueryCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewMemoryQueryCache(time.Hour, 2)
+	cache.Set("a", &entity.QueryInterpretation{Params: &entity.SearchCriteria{Keyword: "a"}})
+	cache.Set("b", &entity.QueryInterpretation{Params: &entity.SearchCriteria{Keyword: "b"}})
+	if _, ok := cache.Get("a"); !ok {
+		t.Fatal("a がキャッシュにありません")
+	}
+	cache.Set("c", &entity.QueryInterpretation{Params: &entity.SearchCriteria{Keyword: "c"}})
+
+	if cache.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", cache.Len())
+	}
+	if _, ok := cache.Get("b"); ok {
+		t.Error("最も長く使われていない b が削除されていません")
+	}
+	for _, key := range []string{"a", "c"} {
+		got, ok := cache.Get(key)
+		if !ok || got.Params.Keyword != key {
+			t.Errorf("Get(%q) = %v, %v", key, got, ok)
+		}
+	}
+}
+
+func TestMemoryQueryCacheDropsExpiredEntries(t *testing.T) {
+	cache := NewMemoryQueryCache(-time.Second, 10)
+	for _, key := range []string{"a", "b", "c"} {
+		cache.Set(key, &entity.QueryInterpretation{Params: &entity.SearchCriteria{}})
+	}
+	if cache.Len() != 0 {
+		t.Errorf("Len() = %d, want 0（期限切れは保存時に取り除く）", cache.Len())
+	}
+	if _, ok := cache.Get("c"); ok {
+		t.Error("期限切れの c が取得できました")
+	}
+}
+
+func TestMemoryQueryCacheReturnsCopy(t *testing.T) {
+	cache := NewMemoryQueryCache(time.Hour, 0)
+	cache.Set("a", &entity.QueryInterpretation{Params: &entity.SearchCriteria{Keyword: "居酒屋"}})
+	got, _ := cache.Get("a")
+	got.Params.Keyword = "変更"
+	if again, _ := cache.Get("a"); again.Params.Keyword != "居酒屋" {
+		t.Errorf("取得した結果の変更がキャッシュに反映されました: %q", again.Params.Keyword)
+	}
+}
+
+func TestFileQueryCacheRemovesExpiredFiles(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewFileQueryCache(dir, -time.Second, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache.Set("a", &entity.QueryInterpretation{Params: &entity.SearchCriteria{Keyword: "a"}})
+	if _, ok := cache.Get("a"); ok {
+		t.Error("期限切れの a が取得できました")
+	}
+	if files, _ := os.ReadDir(dir); len(files) != 0 {
+		t.Errorf("期限切れのファイルが %d 件残っています", len(files))
+	}
+}
+
+func TestFileQueryCacheLimitsEntries(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewFileQueryCache(dir, time.Hour, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, key := range []string{"a", "b", "c"} {
+		cache.Set(key, &entity.QueryInterpretation{Params: &entity.SearchCriteria{Keyword: key}})
+		// 保存した順がファイルの更新時刻で区別できるようにする
+		saved := time.Now().Add(time.Duration(i-3) * time.Minute)
+		if err := os.Chtimes(cache.path(key), saved, saved); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cache.Set("d", &entity.QueryInterpretation{Params: &entity.SearchCriteria{Keyword: "d"}})
+
+	if files, _ := os.ReadDir(dir); len(files) != 2 {
+		t.Errorf("ファイルの数 = %d, want 2", len(files))
+	}
+	for _, key := range []string{"a", "b"} {
+		if _, ok := cache.Get(key); ok {
+			t.Errorf("古く保存した %s が削除されていません", key)
+		}
+	}
+	for _, key := range []string{"c", "d"} {
+		if got, ok := cache.Get(key); !ok || got.Params.Keyword != key {
+			t.Errorf("Get(%q) = %v, %v", key, got, ok)
+		}
+	}
+}
+
+func TestQueryCacheKeyIncludesModels(t *testing.T) {
+	base := queryCacheKey("渋谷 居酒屋", "rev", []string{"gpt-4o", "gpt-4o-mini"})
+	if got := queryCacheKey("  渋谷   居酒屋 ", "rev", []string{"gpt-4o", "gpt-4o-mini"}); got != base {
+		t.Errorf("空白の違いでキーが変わりました: %q != %q", got, base)
+	}
+	if got := queryCacheKey("渋谷 居酒屋", "rev", []string{"gpt-4o-mini"}); got == base {
+		t.Error("抽出に使うモデルが違うのに同じキーになりました")
+	}
+}
@@ -0,0 +1,552 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"restaurant-finder/Domain/entity"
+	"restaurant-finder/Domain/repository"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// duplicateDistanceMeters は名前が一致する店舗を同じ店舗とみなす距離です
+// 名前の一方が他方を含むだけの場合（"鳥貴族" と "鳥貴族 渋谷店"）はこの半分の距離までとします
+const duplicateDistanceMeters = 100
+
+const (
+	// mergeWindow は重複をまとめるために各提供元から取得する店舗数の最小値です
+	mergeWindow = 100
+	// mergeFetchSize は提供元に 1 回で問い合わせる店舗数です（HotPepper API の上限）
+	mergeFetchSize = 100
+	// maxMergeCandidates は重複をまとめるために各提供元から取得する店舗数の上限です
+	maxMergeCandidates = 1000
+	// candidateWindowTTL は取得した店舗を同じ検索条件のページ送り・ファセットの集計に使い回す期間です
+	candidateWindowTTL = time.Minute
+	// maxCandidateWindows は取得した店舗を保持する検索条件の数の上限です
+	maxCandidateWindows = 32
+)
+
+// duplicateCellDegrees は重複の候補を探す区画の大きさ（緯度・経度）です
+// 国内の緯度では東西・南北とも duplicateDistanceMeters より大きく、隣の区画まで探せば足ります
+const duplicateCellDegrees = 0.002
+
+// addressNumberPattern は住所の番地までの部分です（以降の建物名・階数を比較に使いません）
+var addressNumberPattern = regexp.MustCompile(`^(.*?\d+(?:-\d+)*)`)
+
+// addressPrefecturePattern は住所の先頭の都道府県です
+var addressPrefecturePattern = regexp.MustCompile(`^(東京都|北海道|(?:京都|大阪)府|.{2,3}県)`)
+
+// addressDashPattern は番地の区切りに使われるハイフン・長音記号です
+var addressDashPattern = regexp.MustCompile(`(\d)\s*[-ー−‐―－]\s*(\d)`)
+
+// addressBuildingPattern は番地の後の空白です（"2-29-5 3F" の階数を番地と続けて読まないよう区切ります）
+var addressBuildingPattern = regexp.MustCompile(`(\d-?)\s+`)
+
+// addressNumberReplacer は "2丁目29番5号" を "2-29-5" の形にそろえます
+var addressNumberReplacer = strings.NewReplacer("丁目", "-", "番地", "-", "番", "-", "号", "")
+
+// SourceRegistry がレストラン検索のポートを満たすことをコンパイル時に確認する
+var (
+	_ repository.RestaurantSearcher = (*SourceRegistry)(nil)
+	_ repository.RequestDescriber   = (*SourceRegistry)(nil)
+)
+
+// SourceRegistry は登録したすべての提供元に同時に検索し、結果を 1 つの一覧にまとめてからページに分けます
+// 同じ店舗は名前・住所・位置で判定して 1 件にまとめ、見つかったすべての提供元を Sources に残します
+// 各提供元からは先頭から最大 maxMergeCandidates 件を取得します。それを超える店舗は重複をまとめられないため、
+// その場合の Available は重複を含む推定値です
+// 取得した店舗は検索条件ごとに candidateWindowTTL の間保持し、1 回の検索でのページ送り・ファセットの集計では
+// 足りない分だけを提供元に問い合わせます
+type SourceRegistry struct {
+	sources []repository.RestaurantSource
+
+	mu      sync.Mutex
+	windows map[string]*candidateWindow
+	now     func() time.Time
+}
+
+// NewSourceRegistry は提供元を登録した SourceRegistry を作成します。先に登録した提供元の情報と並び順を優先します
+func NewSourceRegistry(sources ...repository.RestaurantSource) *SourceRegistry {
+	registry := &SourceRegistry{windows: make(map[string]*candidateWindow), now: time.Now}
+	for _, source := range sources {
+		registry.Register(source)
+	}
+	return registry
+}
+
+// Register は提供元を追加します
+func (r *SourceRegistry) Register(source repository.RestaurantSource) {
+	if source != nil {
+		r.sources = append(r.sources, source)
+	}
+}
+
+// Sources は登録した提供元の名前を返します
+func (r *SourceRegistry) Sources() []string {
+	names := make([]string, 0, len(r.sources))
+	for _, source := range r.sources {
+		names = append(names, source.Name())
+	}
+	return names
+}
+
+// SearchRestaurants はすべての提供元に同時に検索し、重複をまとめた一覧から Start / Count で指定したページを返します
+// 各提供元からは表示するページまでを含む先頭の店舗（最小 mergeWindow 件、最大 maxMergeCandidates 件）を取得してまとめます
+// 一部の提供元が失敗した場合は残りの結果を返し、すべて失敗した場合だけエラーを返します
+func (r *SourceRegistry) SearchRestaurants(criteria *entity.SearchCriteria) (*entity.RestaurantPage, error) {
+	count := criteria.Count
+	if count <= 0 {
+		count = 10
+	}
+	start := max(criteria.Start, 1)
+	limit := min(max(start-1+count, mergeWindow), maxMergeCandidates)
+
+	sources := r.sourcesFor(criteria)
+	window := r.window(criteria, len(sources))
+	window.mu.Lock()
+	defer window.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for i, source := range sources {
+		if !window.candidates[i].needs(limit) {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			window.candidates[i].fetch(source, criteria, limit)
+		}()
+	}
+	wg.Wait()
+
+	merged := make([]entity.Restaurant, 0)
+	index := newDuplicateIndex()
+	beyondWindow := 0
+	var firstErr error
+	succeeded := 0
+	for i, c := range window.candidates {
+		if c.err != nil {
+			fmt.Printf("警告: 提供元 %s の検索に失敗しました: %v\n", sources[i].Name(), c.err)
+			if firstErr == nil {
+				firstErr = c.err
+			}
+			continue
+		}
+		succeeded++
+		// 取得しなかった店舗は重複をまとめられないため、そのまま総数に数える
+		beyondWindow += max(c.available-len(c.restaurants), 0)
+		for j, restaurant := range c.restaurants {
+			restaurant = withProvenance(restaurant)
+			if k := index.find(restaurant, c.keys[j]); k >= 0 {
+				merged[k] = mergeRestaurants(merged[k], restaurant)
+				index.update(k, merged[k])
+				continue
+			}
+			index.add(len(merged), restaurant, c.keys[j])
+			merged = append(merged, restaurant)
+		}
+	}
+	if succeeded == 0 && firstErr != nil {
+		return nil, firstErr
+	}
+
+	offset := min(start-1, len(merged))
+	return &entity.RestaurantPage{
+		Restaurants: merged[offset:min(offset+count, len(merged))],
+		Available:   len(merged) + beyondWindow,
+		Start:       start,
+	}, nil
+}
+
+// candidateWindow は 1 つの検索条件で各提供元から取得した先頭の店舗です
+type candidateWindow struct {
+	mu         sync.Mutex
+	fetchedAt  time.Time
+	candidates []sourceCandidates
+}
+
+// window は検索条件（ページの指定を除く）に対応する取得済みの店舗を返します
+// 保持期間を過ぎたものは捨て、保持する検索条件が maxCandidateWindows を超えた場合は最も古いものから捨てます
+func (r *SourceRegistry) window(criteria *entity.SearchCriteria, sources int) *candidateWindow {
+	key := candidateWindowKey(criteria)
+	now := r.now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if window, ok := r.windows[key]; ok && now.Sub(window.fetchedAt) < candidateWindowTTL {
+		return window
+	}
+	for k, window := range r.windows {
+		if now.Sub(window.fetchedAt) >= candidateWindowTTL {
+			delete(r.windows, k)
+		}
+	}
+	for len(r.windows) >= maxCandidateWindows {
+		oldest := ""
+		for k, window := range r.windows {
+			if oldest == "" || window.fetchedAt.Before(r.windows[oldest].fetchedAt) {
+				oldest = k
+			}
+		}
+		delete(r.windows, oldest)
+	}
+	window := &candidateWindow{fetchedAt: now, candidates: make([]sourceCandidates, sources)}
+	r.windows[key] = window
+	return window
+}
+
+// candidateWindowKey は取得済みの店舗を探すためのキーです（ページの指定が違う検索は同じキーになります）
+func candidateWindowKey(criteria *entity.SearchCriteria) string {
+	key := *criteria
+	key.Start, key.Count = 0, 0
+	encoded, _ := json.Marshal(key)
+	return string(encoded)
+}
+
+// sourceCandidates は 1 つの提供元から取得した先頭の店舗と、条件に一致する店舗の総数です
+type sourceCandidates struct {
+	restaurants []entity.Restaurant
+	// keys は restaurants の各店舗の重複判定用のキーです
+	keys      []duplicateKey
+	available int
+	// done はこれ以上取得できない（すべて取得した、または失敗した）ことを示します
+	done bool
+	err  error
+}
+
+// needs は先頭の limit 件を揃えるために提供元への問い合わせが必要かを返します
+func (c *sourceCandidates) needs(limit int) bool {
+	return !c.done && len(c.restaurants) < limit
+}
+
+// fetch は提供元から先頭の limit 件までの店舗のうち、まだ取得していない分を mergeFetchSize 件ずつ取得します
+// 最初のページで失敗した場合は err を、2 ページ目以降で失敗した場合はそれまでに取得した店舗を残します
+func (c *sourceCandidates) fetch(source repository.RestaurantSource, criteria *entity.SearchCriteria, limit int) {
+	// 提供元が検索条件を書き換えても他の提供元に影響しないよう複製して渡す
+	sourceCriteria := criteria.Clone()
+
+	for start := len(c.restaurants) + 1; start <= limit; start += mergeFetchSize {
+		sourceCriteria.Start = start
+		sourceCriteria.Count = min(mergeFetchSize, limit-start+1)
+		page, err := source.SearchRestaurants(sourceCriteria)
+		if err != nil {
+			if start == 1 {
+				c.err = err
+			} else {
+				fmt.Printf("警告: 提供元 %s の %d 件目以降を取得できませんでした: %v\n", source.Name(), start, err)
+			}
+			c.done = true
+			return
+		}
+		if page == nil {
+			c.done = true
+			return
+		}
+		c.available = max(page.Available, len(c.restaurants)+len(page.Restaurants))
+		c.restaurants = append(c.restaurants, page.Restaurants...)
+		for _, restaurant := range page.Restaurants {
+			c.keys = append(c.keys, newDuplicateKey(restaurant))
+		}
+		if len(page.Restaurants) < sourceCriteria.Count || len(c.restaurants) >= c.available {
+			c.done = true
+			return
+		}
+	}
+}
+
+// DescribeRequest は提供元に送るリクエストを、リクエストの形を示せる提供元の分だけ改行区切りで返します
+func (r *SourceRegistry) DescribeRequest(criteria *entity.SearchCriteria) string {
+	descriptions := make([]string, 0, len(r.sources))
+	for _, source := range r.sourcesFor(criteria) {
+		if describer, ok := source.(repository.RequestDescriber); ok {
+			if description := describer.DescribeRequest(criteria); description != "" {
+				descriptions = append(descriptions, description)
+			}
+		}
+	}
+	return strings.Join(descriptions, "\n")
+}
+
+// sourcesFor は検索する提供元を返します
+// "<提供元>:<ID>" の形の店舗 ID を指定した場合は、その提供元だけに問い合わせます
+func (r *SourceRegistry) sourcesFor(criteria *entity.SearchCriteria) []repository.RestaurantSource {
+	if name, _, ok := strings.Cut(criteria.ID, ":"); ok {
+		for _, source := range r.sources {
+			if source.Name() == name {
+				return []repository.RestaurantSource{source}
+			}
+		}
+	}
+	return r.sources
+}
+
+// withProvenance は提供元の記録がない店舗に、その店舗自身の提供元を記録します
+func withProvenance(restaurant entity.Restaurant) entity.Restaurant {
+	if len(restaurant.Sources) == 0 && restaurant.Source != "" {
+		restaurant.Sources = []entity.SourceRef{
+			{Source: restaurant.Source, SourceID: restaurant.SourceID, Label: restaurant.Source, URL: restaurant.URL},
+		}
+	}
+	return restaurant
+}
+
+// duplicateKey は重複の判定に使う店舗名・住所の正規化した値と、位置の区画です
+// 店舗ごとに 1 回だけ計算し、比較のたびに正規化しないようにします
+type duplicateKey struct {
+	name    string
+	address string
+	cell    [2]int
+	located bool
+}
+
+// newDuplicateKey は店舗の重複判定用のキーを作成します
+func newDuplicateKey(restaurant entity.Restaurant) duplicateKey {
+	key := duplicateKey{
+		name:    normalizeRestaurantName(restaurant.Name),
+		address: normalizeAddress(restaurant.Address),
+		located: entity.ValidLatLng(restaurant.Lat, restaurant.Lng),
+	}
+	if key.located {
+		key.cell = duplicateCell(restaurant.Lat, restaurant.Lng)
+	}
+	return key
+}
+
+// duplicateCell は位置を duplicateCellDegrees 四方の区画に分けた番号です
+func duplicateCell(lat, lng float64) [2]int {
+	return [2]int{int(math.Floor(lat / duplicateCellDegrees)), int(math.Floor(lng / duplicateCellDegrees))}
+}
+
+// duplicateIndex はまとめた店舗を提供元の ID・名前・住所・位置の区画で引けるようにした索引です
+type duplicateIndex struct {
+	restaurants []entity.Restaurant
+	keys        []duplicateKey
+	bySourceID  map[string]int
+	byName      map[string][]int
+	byAddress   map[string][]int
+	byCell      map[[2]int][]int
+}
+
+// newDuplicateIndex は空の索引を作成します
+func newDuplicateIndex() *duplicateIndex {
+	return &duplicateIndex{
+		bySourceID: make(map[string]int),
+		byName:     make(map[string][]int),
+		byAddress:  make(map[string][]int),
+		byCell:     make(map[[2]int][]int),
+	}
+}
+
+// add は i 番目にまとめた店舗を索引に加えます
+func (x *duplicateIndex) add(i int, restaurant entity.Restaurant, key duplicateKey) {
+	x.restaurants = append(x.restaurants, restaurant)
+	x.keys = append(x.keys, key)
+	x.index(i, restaurant, key)
+}
+
+// update は i 番目の店舗を他の提供元の情報で補った後の内容にします
+// 住所・位置を補った場合は、補った値でも引けるようにします
+func (x *duplicateIndex) update(i int, restaurant entity.Restaurant) {
+	x.restaurants[i] = restaurant
+	key := x.keys[i]
+	if restaurant.Address == "" && !entity.ValidLatLng(restaurant.Lat, restaurant.Lng) {
+		return
+	}
+	updated := newDuplicateKey(restaurant)
+	if updated.address == key.address && updated.located == key.located && updated.cell == key.cell {
+		return
+	}
+	x.keys[i] = updated
+	if key.name == "" {
+		return
+	}
+	if updated.address != "" && updated.address != key.address {
+		x.byAddress[updated.address] = append(x.byAddress[updated.address], i)
+	}
+	if updated.located && (!key.located || updated.cell != key.cell) {
+		x.byCell[updated.cell] = append(x.byCell[updated.cell], i)
+	}
+}
+
+// index は店舗を提供元の ID・名前・住所・位置の区画で引けるようにします
+func (x *duplicateIndex) index(i int, restaurant entity.Restaurant, key duplicateKey) {
+	if restaurant.SourceID != "" {
+		x.bySourceID[restaurant.Source+"\x00"+restaurant.SourceID] = i
+	}
+	if key.name == "" {
+		return
+	}
+	x.byName[key.name] = append(x.byName[key.name], i)
+	if key.address != "" {
+		x.byAddress[key.address] = append(x.byAddress[key.address], i)
+	}
+	if key.located {
+		x.byCell[key.cell] = append(x.byCell[key.cell], i)
+	}
+}
+
+// find は同じ店舗とみなす店舗の位置を返します（見つからない場合は -1、複数ある場合は先にまとめた店舗）
+// 名前が一致する店舗、住所が一致する店舗、近くの区画にある店舗だけを sameRestaurantKeys で比較します
+func (x *duplicateIndex) find(restaurant entity.Restaurant, key duplicateKey) int {
+	found := -1
+	if restaurant.SourceID != "" {
+		if i, ok := x.bySourceID[restaurant.Source+"\x00"+restaurant.SourceID]; ok {
+			found = i
+		}
+	}
+	if key.name == "" {
+		return found
+	}
+	check := func(candidates []int) {
+		for _, i := range candidates {
+			if (found < 0 || i < found) && sameRestaurantKeys(x.restaurants[i], x.keys[i], restaurant, key) {
+				found = i
+			}
+		}
+	}
+	check(x.byName[key.name])
+	if key.address != "" {
+		check(x.byAddress[key.address])
+	}
+	if key.located {
+		for dLat := -1; dLat <= 1; dLat++ {
+			for dLng := -1; dLng <= 1; dLng++ {
+				check(x.byCell[[2]int{key.cell[0] + dLat, key.cell[1] + dLng}])
+			}
+		}
+	}
+	return found
+}
+
+// sameRestaurant は 2 つの店舗が同じ店舗かを名前・住所・位置で判定します
+// 名前が一致し、住所が一致するか近くにある場合に同じ店舗とみなします（チェーン店の別の店舗をまとめないため）
+func sameRestaurant(a, b entity.Restaurant) bool {
+	return sameRestaurantKeys(a, newDuplicateKey(a), b, newDuplicateKey(b))
+}
+
+// sameRestaurantKeys は重複判定用のキーを計算済みの 2 つの店舗が同じ店舗かを判定します
+func sameRestaurantKeys(a entity.Restaurant, keyA duplicateKey, b entity.Restaurant, keyB duplicateKey) bool {
+	if a.Source == b.Source && a.SourceID != "" && a.SourceID == b.SourceID {
+		return true
+	}
+	nameA, nameB := keyA.name, keyB.name
+	if nameA == "" || nameB == "" {
+		return false
+	}
+	exactName := nameA == nameB
+	partialName := min(utf8.RuneCountInString(nameA), utf8.RuneCountInString(nameB)) >= 2 &&
+		(strings.Contains(nameA, nameB) || strings.Contains(nameB, nameA))
+	if !exactName && !partialName {
+		return false
+	}
+
+	if keyA.address != "" && keyA.address == keyB.address {
+		return true
+	}
+	if !keyA.located || !keyB.located {
+		return false
+	}
+	limit := float64(duplicateDistanceMeters)
+	if !exactName {
+		limit /= 2
+	}
+	return entity.HaversineMeters(a.Lat, a.Lng, b.Lat, b.Lng) <= limit
+}
+
+// normalizeRestaurantName は店舗名を比較用に正規化します（空白・記号を除き、かなと英字の表記をそろえます）
+func normalizeRestaurantName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, normalizeText(name))
+}
+
+// normalizeAddress は住所を比較用に正規化します（都道府県と建物名を除き、番地の表記をそろえます）
+func normalizeAddress(address string) string {
+	// 長音記号は normalizeText で除かれるため、先に番地の区切りをハイフンにする
+	text := norm.NFKC.String(address)
+	for range 2 {
+		// "1ー2ー3" のように数字を共有する区切りは 1 回では置き換えきれない
+		text = addressDashPattern.ReplaceAllString(text, "$1-$2")
+	}
+	text = addressNumberReplacer.Replace(text)
+	text = normalizeText(addressBuildingPattern.ReplaceAllString(text, "$1,"))
+	text = addressPrefecturePattern.ReplaceAllString(text, "")
+	text = strings.TrimRight(text, "-")
+	if match := addressNumberPattern.FindString(text); match != "" {
+		return match
+	}
+	return text
+}
+
+// mergeRestaurants は同じ店舗の情報をまとめます
+// 先に見つかった店舗の情報を優先し、ない項目だけを後の店舗で補い、提供元の記録はすべて残します
+func mergeRestaurants(base, other entity.Restaurant) entity.Restaurant {
+	if base.Catch == "" {
+		base.Catch = other.Catch
+	}
+	if base.Address == "" {
+		base.Address = other.Address
+	}
+	if !entity.ValidLatLng(base.Lat, base.Lng) {
+		base.Lat, base.Lng = other.Lat, other.Lng
+	}
+	if base.URL == "" {
+		base.URL = other.URL
+	}
+	if base.PhotoURL == "" {
+		base.PhotoURL = other.PhotoURL
+	}
+	if !base.AccessInfo.Parsed && other.AccessInfo.Parsed {
+		base.Access, base.AccessInfo = other.Access, other.AccessInfo
+	}
+	if base.Genre == (entity.Category{}) {
+		base.Genre = other.Genre
+	}
+	if base.PriceRange == (entity.PriceRange{}) {
+		base.PriceRange = other.PriceRange
+	}
+	if base.Hours == nil && other.Hours != nil {
+		base.OpenText, base.CloseText, base.Hours = other.OpenText, other.CloseText, other.Hours
+	}
+	if base.Capacity == 0 {
+		base.Capacity = other.Capacity
+	}
+
+	facilities := append([]string(nil), base.Facilities...)
+	for _, facility := range other.Facilities {
+		if !base.HasFacility(facility) {
+			facilities = append(facilities, facility)
+		}
+	}
+	base.Facilities = facilities
+
+	sources := append([]entity.SourceRef(nil), base.Sources...)
+	for _, ref := range other.Sources {
+		if !hasSourceRef(sources, ref) {
+			sources = append(sources, ref)
+		}
+	}
+	base.Sources = sources
+	return base
+}
+
+// hasSourceRef は同じ提供元・ID の記録があるかを返します
+func hasSourceRef(sources []entity.SourceRef, ref entity.SourceRef) bool {
+	for _, s := range sources {
+		if s.Source == ref.Source && s.SourceID == ref.SourceID {
+			return true
+		}
+	}
+	return false
+}
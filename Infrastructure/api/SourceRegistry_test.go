@@ -0,0 +1,318 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"restaurant-finder/Domain/entity"
+)
+
+// fakeSource は登録した店舗を Start / Count で区切って返す提供元のフェイクです
+type fakeSource struct {
+	name        string
+	restaurants []entity.Restaurant
+	// available を指定した場合は、登録した店舗より多くの店舗が一致したことにします
+	available int
+	err       error
+	calls     []entity.SearchCriteria
+}
+
+func (f *fakeSource) Name() string {
+	return f.name
+}
+
+func (f *fakeSource) SearchRestaurants(criteria *entity.SearchCriteria) (*entity.RestaurantPage, error) {
+	f.calls = append(f.calls, *criteria)
+	if f.err != nil {
+		return nil, f.err
+	}
+	offset := min(max(criteria.Start-1, 0), len(f.restaurants))
+	return &entity.RestaurantPage{
+		Restaurants: f.restaurants[offset:min(offset+criteria.Count, len(f.restaurants))],
+		Available:   max(f.available, len(f.restaurants)),
+		Start:       criteria.Start,
+	}, nil
+}
+
+// shop はテスト用の店舗を作成します
+func shop(source, id, name, address string, lat, lng float64) entity.Restaurant {
+	return entity.Restaurant{
+		ID: source + ":" + id, Source: source, SourceID: id,
+		Name: name, Address: address, Lat: lat, Lng: lng,
+	}
+}
+
+// numberedShops は名前と位置がすべて異なる n 件の店舗を作成します
+func numberedShops(source string, n int) []entity.Restaurant {
+	shops := make([]entity.Restaurant, n)
+	for i := range shops {
+		shops[i] = shop(source, fmt.Sprint(i+1), fmt.Sprintf("%s店%d", source, i+1), "", 35+float64(i)*0.01, 139)
+	}
+	return shops
+}
+
+func TestSameRestaurant(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b entity.Restaurant
+		want bool
+	}{
+		{
+			"同じ提供元の同じ ID",
+			shop("hotpepper", "J1", "鳥貴族 渋谷店", "", 0, 0), shop("hotpepper", "J1", "別名", "", 0, 0), true,
+		},
+		{
+			"住所の表記が違う同じ店舗",
+			shop("hotpepper", "J1", "鳥貴族 渋谷店", "東京都渋谷区道玄坂2-29-5 3F", 0, 0),
+			shop("favorites", "1", "鳥貴族渋谷店", "渋谷区道玄坂2丁目29番5号", 0, 0), true,
+		},
+		{
+			"番地の後の階数を番地として読まない",
+			shop("hotpepper", "J1", "鳥貴族", "東京都渋谷区道玄坂2-29-5 3F", 0, 0),
+			shop("favorites", "1", "鳥貴族", "東京都渋谷区道玄坂2-29-53", 0, 0), false,
+		},
+		{
+			"住所がなく近くにある同じ名前",
+			shop("hotpepper", "J1", "ＢＡＲ　ＯＮＥ", "", 35.6580, 139.7016),
+			shop("favorites", "1", "Bar One", "", 35.6583, 139.7017), true,
+		},
+		{
+			"離れた場所にあるチェーン店",
+			shop("hotpepper", "J1", "鳥貴族", "", 35.6580, 139.7016),
+			shop("favorites", "1", "鳥貴族", "", 35.6900, 139.7000), false,
+		},
+		{
+			"名前の一方が他方を含む場合は半分の距離まで",
+			shop("hotpepper", "J1", "鳥貴族 渋谷店", "", 35.6580, 139.7016),
+			shop("favorites", "1", "鳥貴族", "", 35.6587, 139.7016), false,
+		},
+		{
+			"名前が違う",
+			shop("hotpepper", "J1", "鳥貴族", "渋谷区道玄坂2-29-5", 0, 0),
+			shop("favorites", "1", "磯丸水産", "渋谷区道玄坂2-29-5", 0, 0), false,
+		},
+		{
+			"位置も住所もない",
+			shop("hotpepper", "J1", "鳥貴族", "", 0, 0), shop("favorites", "1", "鳥貴族", "", 0, 0), false,
+		},
+	}
+	for _, tt := range tests {
+		if got := sameRestaurant(tt.a, tt.b); got != tt.want {
+			t.Errorf("%s: sameRestaurant() = %t, want %t", tt.name, got, tt.want)
+		}
+		// 索引で探した場合も同じ判定になる
+		index := newDuplicateIndex()
+		index.add(0, tt.a, newDuplicateKey(tt.a))
+		if got := index.find(tt.b, newDuplicateKey(tt.b)) == 0; got != tt.want {
+			t.Errorf("%s: duplicateIndex.find() = %t, want %t", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		want    string
+	}{
+		{"東京都渋谷区道玄坂2-29-5 3F", "渋谷区道玄坂2-29-5"},
+		{"渋谷区道玄坂２丁目２９番５号", "渋谷区道玄坂2-29-5"},
+		{"東京都渋谷区道玄坂2ー29ー5 ザ・ハウス1F", "渋谷区道玄坂2-29-5"},
+		{"神奈川県横浜市西区南幸1-1-1", "横浜市西区南幸1-1-1"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := normalizeAddress(tt.address); got != tt.want {
+			t.Errorf("normalizeAddress(%q) = %q, want %q", tt.address, got, tt.want)
+		}
+	}
+}
+
+func TestSourceRegistryMergesProvenance(t *testing.T) {
+	hotpepper := shop("hotpepper", "J1", "鳥貴族 渋谷店", "東京都渋谷区道玄坂2-29-5 3F", 0, 0)
+	hotpepper.Facilities = []string{entity.FacilityFreeDrink}
+	favorite := shop("favorites", "1", "鳥貴族渋谷店", "渋谷区道玄坂2丁目29番5号", 35.6580, 139.7016)
+	favorite.Facilities = []string{entity.FacilityFreeDrink, entity.FacilityPrivateRoom}
+	favorite.Capacity = 40
+	favorite.Sources = []entity.SourceRef{{Source: "favorites", SourceID: "1", Label: "チームのお気に入り"}}
+
+	registry := NewSourceRegistry(
+		&fakeSource{name: "hotpepper", restaurants: []entity.Restaurant{hotpepper}},
+		&fakeSource{name: "favorites", restaurants: []entity.Restaurant{favorite}},
+	)
+	page, err := registry.SearchRestaurants(&entity.SearchCriteria{Count: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Restaurants) != 1 || page.Available != 1 {
+		t.Fatalf("got %d restaurants (available %d), want 1", len(page.Restaurants), page.Available)
+	}
+	got := page.Restaurants[0]
+	if got.ID != hotpepper.ID || got.Address != hotpepper.Address {
+		t.Errorf("先に登録した提供元の情報を優先していません: %+v", got)
+	}
+	if got.Lat != favorite.Lat || got.Capacity != 40 {
+		t.Errorf("ない項目を後の提供元で補っていません: %+v", got)
+	}
+	if want := []string{entity.FacilityFreeDrink, entity.FacilityPrivateRoom}; !reflect.DeepEqual(got.Facilities, want) {
+		t.Errorf("Facilities = %v, want %v", got.Facilities, want)
+	}
+	wantSources := []entity.SourceRef{
+		{Source: "hotpepper", SourceID: "J1", Label: "hotpepper"},
+		{Source: "favorites", SourceID: "1", Label: "チームのお気に入り"},
+	}
+	if !reflect.DeepEqual(got.Sources, wantSources) {
+		t.Errorf("Sources = %+v, want %+v", got.Sources, wantSources)
+	}
+}
+
+func TestSourceRegistryPaginatesMergedList(t *testing.T) {
+	primary := numberedShops("hotpepper", 15)
+	secondary := numberedShops("favorites", 10)
+	// favorites の先頭 5 件は hotpepper の 11〜15 件目と同じ店舗
+	for i := range 5 {
+		secondary[i].Name = primary[10+i].Name
+		secondary[i].Lat, secondary[i].Lng = primary[10+i].Lat, primary[10+i].Lng
+	}
+	registry := NewSourceRegistry(
+		&fakeSource{name: "hotpepper", restaurants: primary},
+		&fakeSource{name: "favorites", restaurants: secondary},
+	)
+
+	tests := []struct {
+		start   int
+		wantIDs []string
+	}{
+		{1, []string{"hotpepper:1", "hotpepper:2", "hotpepper:3", "hotpepper:4", "hotpepper:5", "hotpepper:6", "hotpepper:7", "hotpepper:8"}},
+		{9, []string{"hotpepper:9", "hotpepper:10", "hotpepper:11", "hotpepper:12", "hotpepper:13", "hotpepper:14", "hotpepper:15", "favorites:6"}},
+		{17, []string{"favorites:7", "favorites:8", "favorites:9", "favorites:10"}},
+		{25, nil},
+	}
+	for _, tt := range tests {
+		page, err := registry.SearchRestaurants(&entity.SearchCriteria{Start: tt.start, Count: 8})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for _, r := range page.Restaurants {
+			ids = append(ids, r.ID)
+		}
+		if !reflect.DeepEqual(ids, tt.wantIDs) {
+			t.Errorf("start=%d: IDs = %v, want %v", tt.start, ids, tt.wantIDs)
+		}
+		if page.Available != 20 || page.Start != tt.start {
+			t.Errorf("start=%d: Available = %d, Start = %d, want 20, %d", tt.start, page.Available, page.Start, tt.start)
+		}
+	}
+}
+
+func TestSourceRegistryFetchesCandidatesInBatches(t *testing.T) {
+	large := &fakeSource{name: "hotpepper", restaurants: numberedShops("hotpepper", 250), available: 5000}
+	registry := NewSourceRegistry(large)
+
+	page, err := registry.SearchRestaurants(&entity.SearchCriteria{Start: 201, Count: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Restaurants) != 10 || page.Restaurants[0].ID != "hotpepper:201" {
+		t.Errorf("got %d restaurants starting at %v", len(page.Restaurants), page.Restaurants)
+	}
+	// 取得しなかった店舗は重複をまとめずに総数に含める
+	if page.Available != 5000 {
+		t.Errorf("Available = %d, want 5000", page.Available)
+	}
+	var starts []int
+	for _, call := range large.calls {
+		starts = append(starts, call.Start)
+		if call.Count > mergeFetchSize {
+			t.Errorf("Count = %d, want at most %d", call.Count, mergeFetchSize)
+		}
+	}
+	if want := []int{1, 101, 201}; !reflect.DeepEqual(starts, want) {
+		t.Errorf("starts = %v, want %v", starts, want)
+	}
+}
+
+func TestSourceRegistryFailures(t *testing.T) {
+	failing := &fakeSource{name: "hotpepper", err: errors.New("timeout")}
+	working := &fakeSource{name: "favorites", restaurants: numberedShops("favorites", 3)}
+
+	page, err := NewSourceRegistry(failing, working).SearchRestaurants(&entity.SearchCriteria{Count: 10})
+	if err != nil || len(page.Restaurants) != 3 || page.Available != 3 {
+		t.Errorf("一部の提供元の失敗: page = %+v, err = %v", page, err)
+	}
+	if _, err := NewSourceRegistry(failing).SearchRestaurants(&entity.SearchCriteria{Count: 10}); err == nil {
+		t.Error("すべての提供元が失敗した場合にエラーを返しません")
+	}
+}
+
+func TestSourceRegistryRoutesIDToSource(t *testing.T) {
+	hotpepper := &fakeSource{name: "hotpepper"}
+	favorites := &fakeSource{name: "favorites", restaurants: numberedShops("favorites", 1)}
+	registry := NewSourceRegistry(hotpepper, favorites)
+
+	if _, err := registry.SearchRestaurants(&entity.SearchCriteria{ID: "favorites:1", Count: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if len(hotpepper.calls) != 0 || len(favorites.calls) != 1 {
+		t.Errorf("calls: hotpepper=%d favorites=%d, want 0 and 1", len(hotpepper.calls), len(favorites.calls))
+	}
+}
+
+func TestSourceRegistryReusesFetchedCandidates(t *testing.T) {
+	large := &fakeSource{name: "hotpepper", restaurants: numberedShops("hotpepper", 250), available: 5000}
+	registry := NewSourceRegistry(large)
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	registry.now = func() time.Time { return now }
+
+	// 表示するページとファセットの集計の取得は、足りない分だけを問い合わせる
+	for _, criteria := range []entity.SearchCriteria{
+		{Keyword: "居酒屋", Start: 11, Count: 10},
+		{Keyword: "居酒屋", Start: 1, Count: 100},
+		{Keyword: "居酒屋", Start: 101, Count: 100},
+		{Keyword: "居酒屋", Start: 201, Count: 100},
+		{Keyword: "居酒屋", Start: 1, Count: 10},
+	} {
+		page, err := registry.SearchRestaurants(&criteria)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := fmt.Sprintf("hotpepper:%d", criteria.Start); len(page.Restaurants) == 0 || page.Restaurants[0].ID != want {
+			t.Errorf("start=%d: 先頭の店舗 = %v, want %s", criteria.Start, page.Restaurants, want)
+		}
+	}
+	var starts []int
+	for _, call := range large.calls {
+		starts = append(starts, call.Start)
+	}
+	if want := []int{1, 101, 201}; !reflect.DeepEqual(starts, want) {
+		t.Errorf("starts = %v, want %v", starts, want)
+	}
+
+	// 検索条件が違う場合と、保持期間を過ぎた場合は取得し直す
+	large.calls = nil
+	if _, err := registry.SearchRestaurants(&entity.SearchCriteria{Keyword: "焼き鳥", Count: 10}); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(candidateWindowTTL)
+	if _, err := registry.SearchRestaurants(&entity.SearchCriteria{Keyword: "居酒屋", Count: 10}); err != nil {
+		t.Fatal(err)
+	}
+	if len(large.calls) != 2 {
+		t.Errorf("calls = %d, want 2", len(large.calls))
+	}
+}
+
+func TestSourceRegistryLimitsCandidateWindows(t *testing.T) {
+	registry := NewSourceRegistry(&fakeSource{name: "favorites", restaurants: numberedShops("favorites", 1)})
+	for i := range maxCandidateWindows + 5 {
+		if _, err := registry.SearchRestaurants(&entity.SearchCriteria{Keyword: fmt.Sprint(i), Count: 10}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := len(registry.windows); n != maxCandidateWindows {
+		t.Errorf("保持している検索条件 = %d, want %d", n, maxCandidateWindows)
+	}
+}
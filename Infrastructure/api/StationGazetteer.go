@@ -0,0 +1,231 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+	"restaurant-finder/Domain/entity"
+)
+
+// defaultStationFile は駅データの既定のファイルです
+const defaultStationFile = "stations.json"
+
+// stationFile は駅データのファイルの形式です
+type stationFile struct {
+	Stations []entity.Station `json:"stations"`
+}
+
+// stationKey はクエリ中の駅名を探すための表記（駅名・読み・別表記・ローマ字）です
+type stationKey struct {
+	text    string
+	romaji  bool
+	station int
+}
+
+// stationMention はクエリ中で見つかった駅名です
+type stationMention struct {
+	start, end int
+	station    int
+}
+
+// StationGazetteer はファイルから読み込んだ駅の一覧です
+type StationGazetteer struct {
+	stations []entity.Station
+	// keys は長い表記から順に並べた検索用の表記です（"新宿三丁目" を "新宿" より先に照合する）
+	keys []stationKey
+}
+
+// stationSuffixes は駅名の直後に続く「駅」を表す語です（英語のクエリは "shinjuku station" の形）
+var stationSuffixes = []string{"駅", "えき"}
+
+// romajiStationSuffixes はローマ字の駅名の直後に続く「駅」を表す語です
+var romajiStationSuffixes = []string{" station", " sta.", " sta ", "station", " eki"}
+
+// midpointWords は複数の駅の中間地点で探すことを表す語です
+var midpointWords = []string{"中間", "真ん中", "まんなか", "間くらい", "間あたり", "の間で", "midpoint", "between", "meet in the middle"}
+
+// NewStationGazetteer は駅データのファイルを読み込んで StationGazetteer を作成します。ファイルがない場合は空の一覧になります
+func NewStationGazetteer(path string) (*StationGazetteer, error) {
+	gazetteer := &StationGazetteer{}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return gazetteer, nil
+	}
+	if err != nil {
+		return gazetteer, fmt.Errorf("駅データを読み込めません: %w", err)
+	}
+
+	var file stationFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return gazetteer, fmt.Errorf("駅データの形式が不正です: %w", err)
+	}
+	gazetteer.stations = file.Stations
+	gazetteer.keys = stationKeys(file.Stations)
+	fmt.Printf("駅データを読み込みました: %s (%d件)\n", path, len(file.Stations))
+	return gazetteer, nil
+}
+
+// NewStationGazetteerFromEnv は環境変数 STATION_FILE の駅データ（既定は stations.json）を読み込みます
+// 読み込めない場合も警告を出して空の一覧を返します
+func NewStationGazetteerFromEnv() *StationGazetteer {
+	path := os.Getenv("STATION_FILE")
+	if path == "" {
+		path = defaultStationFile
+	}
+	gazetteer, err := NewStationGazetteer(path)
+	if err != nil {
+		fmt.Printf("警告: %v\n", err)
+	}
+	return gazetteer
+}
+
+// stationKeys は駅ごとの表記を長い順に並べます
+func stationKeys(stations []entity.Station) []stationKey {
+	keys := make([]stationKey, 0, len(stations)*3)
+	for i, station := range stations {
+		for _, text := range append([]string{station.Name, station.Reading}, station.Aliases...) {
+			if text != "" {
+				keys = append(keys, stationKey{text: normalizeStationText(text), station: i})
+			}
+		}
+		if station.Romaji != "" {
+			keys = append(keys, stationKey{text: normalizeStationText(station.Romaji), romaji: true, station: i})
+		}
+	}
+	sort.SliceStable(keys, func(i, j int) bool { return len(keys[i].text) > len(keys[j].text) })
+	return keys
+}
+
+// normalizeStationText は全角・半角と大文字・小文字の違いをなくします（"ヶ" と "ケ" も同じ扱いにします）
+func normalizeStationText(text string) string {
+	text = strings.ToLower(norm.NFKC.String(text))
+	return strings.ReplaceAll(text, "ヶ", "ケ")
+}
+
+// Len は駅の数を返します
+func (g *StationGazetteer) Len() int {
+	if g == nil {
+		return 0
+	}
+	return len(g.stations)
+}
+
+// Find は駅名（"駅" を付けてもよい）・読み・別表記・ローマ字が一致する駅を返します
+func (g *StationGazetteer) Find(name string) (entity.Station, bool) {
+	if g == nil {
+		return entity.Station{}, false
+	}
+	text := strings.TrimSpace(normalizeStationText(name))
+	for _, suffix := range append(stationSuffixes, romajiStationSuffixes...) {
+		text = strings.TrimSpace(strings.TrimSuffix(text, suffix))
+	}
+	for _, key := range g.keys {
+		if key.text == text {
+			return g.stations[key.station], true
+		}
+	}
+	return entity.Station{}, false
+}
+
+// Mentions はクエリに含まれる駅を出現順に返します（同じ駅は 1 度だけ返します）
+// requireSuffix が true の場合は "新宿三丁目駅" "shinjuku station" のように「駅」が続くものだけを返します
+func (g *StationGazetteer) Mentions(text string, requireSuffix bool) []entity.Station {
+	if g == nil {
+		return nil
+	}
+	text = normalizeStationText(text)
+
+	mentions := make([]stationMention, 0)
+	for _, key := range g.keys {
+		for offset := 0; ; {
+			i := strings.Index(text[offset:], key.text)
+			if i < 0 {
+				break
+			}
+			start := offset + i
+			end := start + len(key.text)
+			offset = end
+			if overlapsMention(mentions, start, end) {
+				continue
+			}
+			if key.romaji && !isWordBoundary(text, start, end) {
+				continue
+			}
+			if requireSuffix && !hasStationSuffix(text[end:], key.romaji) {
+				continue
+			}
+			mentions = append(mentions, stationMention{start: start, end: end, station: key.station})
+		}
+	}
+	sort.Slice(mentions, func(i, j int) bool { return mentions[i].start < mentions[j].start })
+
+	seen := make(map[int]bool)
+	stations := make([]entity.Station, 0, len(mentions))
+	for _, m := range mentions {
+		if !seen[m.station] {
+			seen[m.station] = true
+			stations = append(stations, g.stations[m.station])
+		}
+	}
+	return stations
+}
+
+// Nearest は指定した地点に最も近い駅を返します
+func (g *StationGazetteer) Nearest(lat, lng float64) (entity.Station, bool) {
+	if g == nil || len(g.stations) == 0 {
+		return entity.Station{}, false
+	}
+	nearest, best := 0, math.MaxFloat64
+	for i, station := range g.stations {
+		if d := entity.HaversineMeters(lat, lng, station.Lat, station.Lng); d < best {
+			nearest, best = i, d
+		}
+	}
+	return g.stations[nearest], true
+}
+
+// overlapsMention は既に見つかった駅名と範囲が重なるかを返します
+func overlapsMention(mentions []stationMention, start, end int) bool {
+	for _, m := range mentions {
+		if start < m.end && m.start < end {
+			return true
+		}
+	}
+	return false
+}
+
+// isWordBoundary はローマ字の駅名が単語の途中ではないかを返します
+func isWordBoundary(text string, start, end int) bool {
+	isLetter := func(b byte) bool { return b >= 'a' && b <= 'z' }
+	if start > 0 && isLetter(text[start-1]) {
+		return false
+	}
+	return end >= len(text) || !isLetter(text[end])
+}
+
+// hasStationSuffix は駅名の直後が「駅」を表す語かを返します
+func hasStationSuffix(rest string, romaji bool) bool {
+	suffixes := stationSuffixes
+	if romaji {
+		suffixes = romajiStationSuffixes
+		rest += " "
+	}
+	for _, suffix := range suffixes {
+		if strings.HasPrefix(rest, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// WantsMidpoint はクエリが複数の駅の中間地点で探すことを求めているかを返します
+func WantsMidpoint(text string) bool {
+	return containsAny(normalizeStationText(text), midpointWords...)
+}
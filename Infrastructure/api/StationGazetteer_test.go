@@ -0,0 +1,115 @@
+package api
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"restaurant-finder/Domain/entity"
+)
+
+// newTestGazetteer はテスト用の駅データで StationGazetteer を作成します
+func newTestGazetteer(t *testing.T) *StationGazetteer {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "stations.json")
+	data := `{"stations": [
+		{"name": "新宿", "reading": "しんじゅく", "romaji": "shinjuku", "lat": 35.690921, "lng": 139.700258},
+		{"name": "新宿三丁目", "reading": "しんじゅくさんちょうめ", "romaji": "shinjuku-sanchome", "lat": 35.690846, "lng": 139.706549},
+		{"name": "渋谷", "reading": "しぶや", "romaji": "shibuya", "lat": 35.658034, "lng": 139.701636},
+		{"name": "池袋", "reading": "いけぶくろ", "romaji": "ikebukuro", "lat": 35.728926, "lng": 139.71038},
+		{"name": "市ヶ谷", "reading": "いちがや", "romaji": "ichigaya", "aliases": ["市ケ谷"], "lat": 35.691173, "lng": 139.735241}
+	]}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	gazetteer, err := NewStationGazetteer(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return gazetteer
+}
+
+func TestStationGazetteerFind(t *testing.T) {
+	g := newTestGazetteer(t)
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"新宿", "新宿"},
+		{"新宿駅", "新宿"},
+		{"しぶや", "渋谷"},
+		{"Shibuya Station", "渋谷"},
+		{"ＩＫＥＢＵＫＵＲＯ", "池袋"},
+		{"市ケ谷", "市ヶ谷"},
+		{"新宿三丁目", "新宿三丁目"},
+		{"横浜", ""},
+	}
+	for _, tt := range tests {
+		station, ok := g.Find(tt.name)
+		if got := station.Name; got != tt.want || ok != (tt.want != "") {
+			t.Errorf("Find(%q) = (%q, %t), want %q", tt.name, got, ok, tt.want)
+		}
+	}
+}
+
+func TestStationGazetteerMentions(t *testing.T) {
+	g := newTestGazetteer(t)
+	tests := []struct {
+		text          string
+		requireSuffix bool
+		want          []string
+	}{
+		{"新宿三丁目駅の近くで焼き鳥", true, []string{"新宿三丁目"}},
+		{"渋谷と池袋の中間で飲みたい", false, []string{"渋谷", "池袋"}},
+		{"渋谷と池袋の中間で飲みたい", true, nil},
+		{"izakaya near shibuya station", true, []string{"渋谷"}},
+		{"shibuyaramen", false, nil},
+		{"新宿駅と新宿で", false, []string{"新宿"}},
+		{"市ヶ谷駅 ランチ", true, []string{"市ヶ谷"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, station := range g.Mentions(tt.text, tt.requireSuffix) {
+			got = append(got, station.Name)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Mentions(%q, %t) = %v, want %v", tt.text, tt.requireSuffix, got, tt.want)
+		}
+	}
+}
+
+func TestStationGazetteerNearestAndMidpoint(t *testing.T) {
+	g := newTestGazetteer(t)
+	shinjuku, _ := g.Find("新宿")
+	ikebukuro, _ := g.Find("池袋")
+
+	lat, lng := entity.Midpoint(nil)
+	if lat != 0 || lng != 0 {
+		t.Errorf("entity.Midpoint(nil) = (%v, %v)", lat, lng)
+	}
+	lat, lng = entity.Midpoint([]entity.Station{shinjuku, ikebukuro})
+	if math.Abs(lat-(shinjuku.Lat+ikebukuro.Lat)/2) > 1e-4 || math.Abs(lng-(shinjuku.Lng+ikebukuro.Lng)/2) > 1e-4 {
+		t.Errorf("entity.Midpoint() = (%v, %v)", lat, lng)
+	}
+	if nearest, ok := g.Nearest(35.6585, 139.7010); !ok || nearest.Name != "渋谷" {
+		t.Errorf("Nearest() = %q, %t", nearest.Name, ok)
+	}
+	if _, ok := (&StationGazetteer{}).Nearest(35.6585, 139.7010); ok {
+		t.Error("駅がないのに Nearest() が駅を返しました")
+	}
+}
+
+func TestWantsMidpoint(t *testing.T) {
+	for text, want := range map[string]bool{
+		"渋谷と池袋の中間":                      true,
+		"新宿と渋谷の真ん中あたり":                  true,
+		"meet in the middle of shibuya": true,
+		"渋谷 居酒屋":                        false,
+	} {
+		if got := WantsMidpoint(text); got != want {
+			t.Errorf("WantsMidpoint(%q) = %t, want %t", text, got, want)
+		}
+	}
+}
@@ -0,0 +1,362 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"restaurant-finder/Domain/entity"
+)
+
+// defaultSynonymFile は同義語辞書の既定のファイルです
+const defaultSynonymFile = "synonyms.json"
+
+// synonymFields は同義語で対応付けられる項目です
+var synonymFields = map[string]bool{
+	"location":    true,
+	"large_area":  true,
+	"middle_area": true,
+	"small_area":  true,
+	"genre":       true,
+	"budget":      true,
+	"keyword":     true,
+}
+
+// synonymFile は辞書ファイルの形式です
+type synonymFile struct {
+	Entries []entity.Synonym `json:"entries"`
+}
+
+// SynonymStore はファイルから読み込んだ同義語辞書です。管理画面から編集するとファイルに保存します
+type SynonymStore struct {
+	mu       sync.RWMutex
+	path     string
+	entries  []entity.Synonym
+	revision string
+	// loadErr はファイルを読み込めなかった理由です。読み込めなかった場合は、元のファイルを空の辞書で
+	// 上書きしないよう編集を受け付けません
+	loadErr error
+}
+
+// NewSynonymStore は辞書ファイルを読み込んで SynonymStore を作成します。ファイルがない場合は空の辞書になります
+// ファイルを読み込めない場合は空の辞書とエラーを返し、その辞書は編集できません
+func NewSynonymStore(path string) (*SynonymStore, error) {
+	store := &SynonymStore{path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		store.loadErr = fmt.Errorf("同義語辞書を読み込めません: %w", err)
+		return store, store.loadErr
+	}
+
+	var file synonymFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		store.loadErr = fmt.Errorf("同義語辞書の形式が不正です: %w", err)
+		return store, store.loadErr
+	}
+	store.entries = file.Entries
+	store.revision = synonymRevision(file.Entries)
+	fmt.Printf("同義語辞書を読み込みました: %s (%d件)\n", path, len(file.Entries))
+	return store, nil
+}
+
+// NewSynonymStoreFromEnv は環境変数 SYNONYM_FILE の辞書ファイル（既定は synonyms.json）を読み込みます
+// 読み込めない場合も警告を出して空の辞書を返します
+func NewSynonymStoreFromEnv() *SynonymStore {
+	path := os.Getenv("SYNONYM_FILE")
+	if path == "" {
+		path = defaultSynonymFile
+	}
+	store, err := NewSynonymStore(path)
+	if err != nil {
+		fmt.Printf("警告: %v\n", err)
+	}
+	return store
+}
+
+// List は登録されている同義語を別名の順に返します
+func (s *SynonymStore) List() []entity.Synonym {
+	if s == nil {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := append([]entity.Synonym(nil), s.entries...)
+	sort.Slice(list, func(i, j int) bool { return list[i].Alias < list[j].Alias })
+	return list
+}
+
+// Lookup は語に一致する同義語を返します（正規化して比較します）
+func (s *SynonymStore) Lookup(term string) (entity.Synonym, bool) {
+	if s == nil {
+		return entity.Synonym{}, false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	normalized := normalizeText(term)
+	for _, syn := range s.entries {
+		if normalizeText(syn.Alias) == normalized {
+			return syn, true
+		}
+	}
+	return entity.Synonym{}, false
+}
+
+// FindInText は文中に含まれる同義語を、長い別名から順に返します
+func (s *SynonymStore) FindInText(text string) []entity.Synonym {
+	if s == nil {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	normalized := normalizeText(text)
+	found := make([]entity.Synonym, 0)
+	for _, syn := range s.entries {
+		if alias := normalizeText(syn.Alias); alias != "" && strings.Contains(normalized, alias) {
+			found = append(found, syn)
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool {
+		return utf8.RuneCountInString(found[i].Alias) > utf8.RuneCountInString(found[j].Alias)
+	})
+	return found
+}
+
+// Add は同義語を登録します。同じ別名がある場合は置き換えます
+func (s *SynonymStore) Add(syn entity.Synonym) error {
+	syn.Alias = strings.TrimSpace(syn.Alias)
+	syn.Code = strings.TrimSpace(syn.Code)
+	syn.Name = strings.TrimSpace(syn.Name)
+	syn.Keyword = strings.TrimSpace(syn.Keyword)
+	if err := validateSynonym(syn); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// 保存に成功するまでは辞書を変更しないよう、コピーを編集してから入れ替える
+	entries := slices.Clone(s.entries)
+	replaced := false
+	for i, existing := range entries {
+		if normalizeText(existing.Alias) == normalizeText(syn.Alias) {
+			entries[i] = syn
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		entries = append(entries, syn)
+	}
+	return s.save(entries)
+}
+
+// Remove は別名を指定して同義語を削除します
+func (s *SynonymStore) Remove(alias string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// 登録時と同じく正規化して比較し、全角・半角やかなの違いがあっても削除できるようにする
+	normalized := normalizeText(alias)
+	entries := make([]entity.Synonym, 0, len(s.entries))
+	for _, syn := range s.entries {
+		if normalizeText(syn.Alias) != normalized {
+			entries = append(entries, syn)
+		}
+	}
+	if len(entries) == len(s.entries) {
+		return fmt.Errorf("同義語「%s」は登録されていません", alias)
+	}
+	return s.save(entries)
+}
+
+// Revision は辞書の内容から計算した版です。辞書を編集すると変わります
+func (s *SynonymStore) Revision() string {
+	if s == nil {
+		return ""
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.revision
+}
+
+// synonymRevision は辞書の内容のハッシュを返します
+func synonymRevision(entries []entity.Synonym) string {
+	if len(entries) == 0 {
+		return ""
+	}
+	data, _ := json.Marshal(entries)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:4])
+}
+
+// save は編集後の辞書をファイルに書き出し、書き出せた場合だけ辞書を入れ替えます（一時ファイルに書いてから置き換えます）
+func (s *SynonymStore) save(entries []entity.Synonym) error {
+	if s.loadErr != nil {
+		return fmt.Errorf("辞書ファイル %s を読み込めなかったため保存しません（ファイルを直してから再起動してください）: %w", s.path, s.loadErr)
+	}
+
+	data, err := json.MarshalIndent(synonymFile{Entries: entries}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("同義語辞書を保存できません: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".synonyms-*.json")
+	if err != nil {
+		return fmt.Errorf("同義語辞書を保存できません: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("同義語辞書を保存できません: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("同義語辞書を保存できません: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("同義語辞書を保存できません: %w", err)
+	}
+	s.entries = entries
+	s.revision = synonymRevision(entries)
+	return nil
+}
+
+// validateSynonym は同義語の入力を検証します
+func validateSynonym(syn entity.Synonym) error {
+	if syn.Alias == "" {
+		return fmt.Errorf("別名を入力してください")
+	}
+	if !synonymFields[syn.Field] {
+		return fmt.Errorf("項目「%s」は指定できません", syn.Field)
+	}
+	if syn.Field == "keyword" {
+		if syn.Keyword == "" {
+			return fmt.Errorf("キーワードを入力してください")
+		}
+		return nil
+	}
+	if syn.Field == "location" && syn.Code != "" {
+		return fmt.Errorf("地名（location）はコードではなく名称で指定してください")
+	}
+	if syn.Code == "" && syn.Name == "" {
+		return fmt.Errorf("コードまたは名称を入力してください")
+	}
+	return nil
+}
+
+// synonymOrder は同義語を適用する抽出項目の順序です
+var synonymOrder = []string{"location", "large_area", "middle_area", "small_area", "genre", "budget", "keyword"}
+
+// applySynonyms は抽出項目に同義語辞書を適用します
+// 名称で登録された同義語は項目の語を置き換え、コードで登録された同義語はそのコードを返します
+func applySynonyms(aiParams map[string]string, synonyms *SynonymStore) (resolved map[string]string, codes map[string]string, expansions []string, applied []entity.Synonym) {
+	resolved = make(map[string]string, len(aiParams))
+	for k, v := range aiParams {
+		resolved[k] = v
+	}
+	codes = make(map[string]string)
+
+	for _, field := range synonymOrder {
+		term := aiParams[field]
+		if term == "" {
+			continue
+		}
+		syn, ok := synonyms.Lookup(term)
+		if !ok {
+			continue
+		}
+		fmt.Printf("同義語を適用: %s「%s」-> %+v\n", field, term, syn)
+		delete(resolved, field)
+		expansions = collectSynonym(syn, resolved, codes, expansions)
+		applied = append(applied, syn)
+	}
+	return resolved, codes, expansions, applied
+}
+
+// synonymsFromText は文中の同義語を取り除き、対応する項目・コード・キーワードを返します（LLM を使わない場合に使用）
+func synonymsFromText(text string, found []entity.Synonym) (resolved map[string]string, codes map[string]string, expansions []string, applied []entity.Synonym) {
+	resolved = make(map[string]string)
+	codes = make(map[string]string)
+
+	remaining := text
+	for _, syn := range found {
+		// FindInText と同じく正規化して照合する
+		// より長い別名に含まれていて既に取り除かれた場合は適用しない
+		var ok bool
+		remaining, ok = removeNormalized(remaining, normalizeText(syn.Alias))
+		if !ok {
+			continue
+		}
+		expansions = collectSynonym(syn, resolved, codes, expansions)
+		applied = append(applied, syn)
+	}
+	resolved["keyword"] = strings.Join(strings.Fields(remaining), " ")
+	return resolved, codes, expansions, applied
+}
+
+// removeNormalized は文中で正規化すると alias（正規化済み）に一致する部分をすべて空白に置き換えます
+// 元の表記（半角カナやカタカナなど）のまま残りの語を返すため、文字列全体ではなく元の文字の範囲ごとに照合します
+func removeNormalized(text, alias string) (string, bool) {
+	if alias == "" {
+		return text, false
+	}
+	aliasLen := utf8.RuneCountInString(alias)
+	runes := []rune(text)
+	var b strings.Builder
+	found := false
+	for i := 0; i < len(runes); {
+		end := -1
+		// 空白や長音記号など正規化で消える文字からは照合を始めない（前の語との区切りを残すため）
+		for j := i + 1; j <= len(runes) && normalizeText(string(runes[i])) != ""; j++ {
+			normalized := normalizeText(string(runes[i:j]))
+			// 次の文字が半角の濁点などで結合される場合（"ｼ" の後の "ﾞ"）は一致とみなさない
+			if normalized == alias && (j == len(runes) || strings.HasPrefix(normalizeText(string(runes[i:j+1])), alias)) {
+				end = j
+				break
+			}
+			// 濁点の結合などで 1 文字減ることがあるため、1 文字長くなるまでは続けて照合する
+			if utf8.RuneCountInString(normalized) > aliasLen+1 {
+				break
+			}
+		}
+		if end < 0 {
+			b.WriteRune(runes[i])
+			i++
+			continue
+		}
+		b.WriteString(" ")
+		found = true
+		i = end
+	}
+	return b.String(), found
+}
+
+// collectSynonym は 1 件の同義語を項目・コード・キーワードに振り分けます
+func collectSynonym(syn entity.Synonym, resolved map[string]string, codes map[string]string, expansions []string) []string {
+	switch {
+	case syn.Field == "keyword":
+		// キーワードの同義語はキーワードの追加だけを行う
+	case syn.Code != "":
+		codes[syn.Field] = syn.Code
+	case syn.Name != "" && resolved[syn.Field] == "":
+		resolved[syn.Field] = syn.Name
+	}
+	if syn.Keyword != "" {
+		expansions = append(expansions, syn.Keyword)
+	}
+	return expansions
+}
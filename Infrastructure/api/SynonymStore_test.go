@@ -0,0 +1,107 @@
+package api
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"restaurant-finder/Domain/entity"
+)
+
+func TestRemoveNormalized(t *testing.T) {
+	tests := []struct {
+		text  string
+		alias string
+		want  string
+		found bool
+	}{
+		{"ハチ公前 焼き鳥", "はち公前", "  焼き鳥", true},
+		{"ﾊﾁ公前で焼き鳥", "はち公前", " で焼き鳥", true},
+		{"はちこうまえ 焼き鳥", "はちこうまえ", "  焼き鳥", true},
+		{"ラーメン 渋谷 ラーメン", "らめん", "  渋谷  ", true},
+		{"ｼﾞﾝｼﾞｬｰ", "し", "ｼﾞﾝｼﾞｬｰ", false},
+		{"渋谷 居酒屋", "新宿", "渋谷 居酒屋", false},
+		{"渋谷", "", "渋谷", false},
+	}
+	for _, tt := range tests {
+		got, found := removeNormalized(tt.text, tt.alias)
+		if got != tt.want || found != tt.found {
+			t.Errorf("removeNormalized(%q, %q) = (%q, %t), want (%q, %t)", tt.text, tt.alias, got, found, tt.want, tt.found)
+		}
+	}
+}
+
+func TestSynonymsFromTextMatchesVariantSpelling(t *testing.T) {
+	store := &SynonymStore{entries: []entity.Synonym{
+		{Alias: "ハチ公前", Field: "location", Name: "渋谷"},
+		{Alias: "のみや", Field: "genre", Code: "G001"},
+	}}
+	text := "ﾊﾁ公前 ノミヤ 個室"
+	resolved, codes, _, applied := synonymsFromText(text, store.FindInText(text))
+	if len(applied) != 2 {
+		t.Fatalf("applied = %+v, want 2 件", applied)
+	}
+	if resolved["location"] != "渋谷" || codes["genre"] != "G001" {
+		t.Errorf("resolved = %v, codes = %v", resolved, codes)
+	}
+	if resolved["keyword"] != "個室" {
+		t.Errorf("keyword = %q, want %q", resolved["keyword"], "個室")
+	}
+}
+
+func TestSynonymStoreRemoveNormalizesAlias(t *testing.T) {
+	store, err := NewSynonymStore(filepath.Join(t.TempDir(), "synonyms.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Add(entity.Synonym{Alias: "ハチ公前", Field: "location", Name: "渋谷"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Add(entity.Synonym{Alias: "ﾊﾁ公前", Field: "location", Name: "渋谷駅"}); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(store.List()); n != 1 {
+		t.Fatalf("表記の違う別名が重複して登録されました: %d 件", n)
+	}
+	if err := store.Remove("はち公前"); err != nil {
+		t.Fatalf("Remove() = %v", err)
+	}
+	if n := len(store.List()); n != 0 {
+		t.Errorf("削除後の件数 = %d", n)
+	}
+	if err := store.Remove("はち公前"); err == nil {
+		t.Error("登録されていない別名の削除でエラーになりません")
+	}
+}
+
+func TestSynonymStoreKeepsEntriesWhenSaveFails(t *testing.T) {
+	// 存在しないディレクトリの辞書は保存できない
+	store, err := NewSynonymStore(filepath.Join(t.TempDir(), "missing", "synonyms.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Add(entity.Synonym{Alias: "ハチ公前", Field: "location", Name: "渋谷"}); err == nil {
+		t.Fatal("保存できないのにエラーになりません")
+	}
+	if n := len(store.List()); n != 0 || store.Revision() != "" {
+		t.Errorf("保存に失敗した同義語が辞書に残りました: %d 件, revision %q", n, store.Revision())
+	}
+}
+
+func TestSynonymStoreRefusesToOverwriteUnreadableFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "synonyms.json")
+	broken := []byte(`{"entries": [`)
+	if err := os.WriteFile(path, broken, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	store, err := NewSynonymStore(path)
+	if err == nil {
+		t.Fatal("形式が不正な辞書でエラーになりません")
+	}
+	if err := store.Add(entity.Synonym{Alias: "ハチ公前", Field: "location", Name: "渋谷"}); err == nil {
+		t.Error("読み込めなかった辞書に保存しました")
+	}
+	if data, _ := os.ReadFile(path); string(data) != string(broken) {
+		t.Errorf("辞書ファイルが書き換えられました: %s", data)
+	}
+}
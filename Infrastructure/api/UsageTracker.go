@@ -0,0 +1,149 @@
+package api
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"restaurant-finder/Domain/entity"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// modelPrice はモデルごとの 100 万トークンあたりの料金（USD）です
+type modelPrice struct {
+	Prompt     float64
+	Completion float64
+}
+
+// modelPrices は推定コストの計算に使う料金表です
+var modelPrices = map[string]modelPrice{
+	openai.GPT4o:     {Prompt: 2.50, Completion: 10.00},
+	openai.GPT4oMini: {Prompt: 0.15, Completion: 0.60},
+}
+
+// estimateUsage は OpenAI のレスポンスの Usage から TokenUsage を作成します
+func estimateUsage(model string, usage openai.Usage) entity.TokenUsage {
+	price, ok := modelPrices[model]
+	if !ok {
+		// 料金表にないモデルは GPT-4o の料金で見積もる
+		price = modelPrices[openai.GPT4o]
+	}
+	cost := float64(usage.PromptTokens)*price.Prompt/1_000_000 +
+		float64(usage.CompletionTokens)*price.Completion/1_000_000
+	return entity.TokenUsage{
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		TotalTokens:      usage.TotalTokens,
+		EstimatedCostUSD: cost,
+	}
+}
+
+// dailyUsage は 1 日分の集計です
+type dailyUsage struct {
+	total    entity.TokenUsage
+	byClient map[string]entity.TokenUsage
+	// reserved は呼び出し中の LLM の見積もりの合計額です（Record で実際の利用量に置き換えます）
+	reserved float64
+}
+
+// UsageTracker は LLM の利用量を日別・クライアント別に集計し、1 日の上限額を管理します
+type UsageTracker struct {
+	mu         sync.Mutex
+	dailyLimit float64
+	days       map[string]*dailyUsage
+	now        func() time.Time
+}
+
+// NewUsageTracker は新しい UsageTracker を作成します。dailyLimit が 0 以下の場合は上限なしです
+func NewUsageTracker(dailyLimit float64) *UsageTracker {
+	return &UsageTracker{
+		dailyLimit: dailyLimit,
+		days:       make(map[string]*dailyUsage),
+		now:        time.Now,
+	}
+}
+
+// NewUsageTrackerFromEnv は環境変数 OPENAI_DAILY_COST_LIMIT_USD の上限額で UsageTracker を作成します
+func NewUsageTrackerFromEnv() *UsageTracker {
+	var limit float64
+	if v := os.Getenv("OPENAI_DAILY_COST_LIMIT_USD"); v != "" {
+		parsed, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			fmt.Printf("警告: OPENAI_DAILY_COST_LIMIT_USD が不正です: %v\n", err)
+		} else {
+			limit = parsed
+		}
+	}
+	return NewUsageTracker(limit)
+}
+
+// dayKey は集計に使う日付キーを返します
+func (t *UsageTracker) dayKey() string {
+	return t.now().Format("2006-01-02")
+}
+
+// today は本日の集計を返します。日付が変わっていたら過去の集計は破棄します（呼び出し側でロックします）
+func (t *UsageTracker) today() *dailyUsage {
+	key := t.dayKey()
+	day, ok := t.days[key]
+	if !ok {
+		t.days = map[string]*dailyUsage{}
+		day = &dailyUsage{byClient: make(map[string]entity.TokenUsage)}
+		t.days[key] = day
+	}
+	return day
+}
+
+// Reserve は見積もった利用額を確保します。上限なしの場合は常に確保します
+// 本日の利用額と確保中の額に見積もりを足すと上限を超える場合は、確保せずに false を返します
+func (t *UsageTracker) Reserve(estimatedCostUSD float64) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	day := t.today()
+	if t.dailyLimit > 0 && day.total.EstimatedCostUSD+day.reserved+estimatedCostUSD > t.dailyLimit {
+		return false
+	}
+	day.reserved += estimatedCostUSD
+	return true
+}
+
+// Record は確保した額を解放し、利用量をクライアントごとに加算します
+func (t *UsageTracker) Record(clientID string, reservedCostUSD float64, usage entity.TokenUsage) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	day := t.today()
+	// 確保した後に日付が変わった場合は、新しい日の確保額を負にしない
+	day.reserved = max(day.reserved-reservedCostUSD, 0)
+	day.total = day.total.Add(usage)
+	day.byClient[clientID] = day.byClient[clientID].Add(usage)
+
+	fmt.Printf("LLM 利用量 - クライアント: %s, 今回: %d tokens ($%.4f), 本日合計: $%.4f\n",
+		clientID, usage.TotalTokens, usage.EstimatedCostUSD, day.total.EstimatedCostUSD)
+}
+
+// DailyTotal は本日の合計利用量を返します
+func (t *UsageTracker) DailyTotal() entity.TokenUsage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if day, ok := t.days[t.dayKey()]; ok {
+		return day.total
+	}
+	return entity.TokenUsage{}
+}
+
+// ClientTotal は本日のクライアント別の利用量を返します
+func (t *UsageTracker) ClientTotal(clientID string) entity.TokenUsage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if day, ok := t.days[t.dayKey()]; ok {
+		return day.byClient[clientID]
+	}
+	return entity.TokenUsage{}
+}
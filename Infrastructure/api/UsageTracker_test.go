@@ -0,0 +1,144 @@
+package api
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"restaurant-finder/Domain/entity"
+)
+
+// newTestUsageTracker は時刻を差し替えられる UsageTracker を作成します
+func newTestUsageTracker(dailyLimit float64, now *time.Time) *UsageTracker {
+	tracker := NewUsageTracker(dailyLimit)
+	tracker.now = func() time.Time { return *now }
+	return tracker
+}
+
+func TestUsageTrackerTotals(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.Local)
+	tracker := newTestUsageTracker(0, &now)
+
+	tracker.Record("a", 0, entity.TokenUsage{PromptTokens: 100, CompletionTokens: 20, TotalTokens: 120, EstimatedCostUSD: 0.01})
+	tracker.Record("b", 0, entity.TokenUsage{PromptTokens: 50, CompletionTokens: 10, TotalTokens: 60, EstimatedCostUSD: 0.005})
+	tracker.Record("a", 0, entity.TokenUsage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15, EstimatedCostUSD: 0.001})
+
+	tests := []struct {
+		name string
+		got  entity.TokenUsage
+		want entity.TokenUsage
+	}{
+		{"クライアント a", tracker.ClientTotal("a"), entity.TokenUsage{PromptTokens: 110, CompletionTokens: 25, TotalTokens: 135, EstimatedCostUSD: 0.011}},
+		{"クライアント b", tracker.ClientTotal("b"), entity.TokenUsage{PromptTokens: 50, CompletionTokens: 10, TotalTokens: 60, EstimatedCostUSD: 0.005}},
+		{"未使用のクライアント", tracker.ClientTotal("c"), entity.TokenUsage{}},
+		{"本日の合計", tracker.DailyTotal(), entity.TokenUsage{PromptTokens: 160, CompletionTokens: 35, TotalTokens: 195, EstimatedCostUSD: 0.016}},
+	}
+	for _, tt := range tests {
+		if tt.got.PromptTokens != tt.want.PromptTokens || tt.got.CompletionTokens != tt.want.CompletionTokens ||
+			tt.got.TotalTokens != tt.want.TotalTokens || !almostEqual(tt.got.EstimatedCostUSD, tt.want.EstimatedCostUSD) {
+			t.Errorf("%s = %+v, want %+v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestUsageTrackerResetsOnNewDay(t *testing.T) {
+	now := time.Date(2026, 10, 19, 23, 59, 0, 0, time.Local)
+	tracker := newTestUsageTracker(0.05, &now)
+
+	if !tracker.Reserve(0.04) {
+		t.Fatal("上限内の見積もりを確保できません")
+	}
+	tracker.Record("a", 0.04, entity.TokenUsage{TotalTokens: 100, EstimatedCostUSD: 0.04})
+	if tracker.Reserve(0.02) {
+		t.Fatal("上限を超える見積もりを確保しました")
+	}
+
+	now = now.Add(2 * time.Minute)
+	if got := tracker.DailyTotal(); got != (entity.TokenUsage{}) {
+		t.Errorf("翌日の合計 = %+v, want 0", got)
+	}
+	if got := tracker.ClientTotal("a"); got != (entity.TokenUsage{}) {
+		t.Errorf("翌日のクライアント a = %+v, want 0", got)
+	}
+	if !tracker.Reserve(0.02) {
+		t.Error("翌日に見積もりを確保できません")
+	}
+}
+
+func TestUsageTrackerReserve(t *testing.T) {
+	tests := []struct {
+		name       string
+		dailyLimit float64
+		used       float64
+		reserved   []float64
+		estimate   float64
+		want       bool
+	}{
+		{"上限なし", 0, 100, []float64{50}, 10, true},
+		{"上限内", 0.10, 0.05, nil, 0.03, true},
+		{"上限ちょうど", 0.10, 0.05, nil, 0.05, true},
+		{"利用額だけで上限を超える", 0.10, 0.08, nil, 0.03, false},
+		{"確保中の額を含めると上限を超える", 0.10, 0.02, []float64{0.04, 0.03}, 0.02, false},
+		{"利用額が上限に達している", 0.10, 0.10, nil, 0.001, false},
+	}
+	for _, tt := range tests {
+		now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.Local)
+		tracker := newTestUsageTracker(tt.dailyLimit, &now)
+		tracker.Record("a", 0, entity.TokenUsage{EstimatedCostUSD: tt.used})
+		for _, r := range tt.reserved {
+			tracker.Reserve(r)
+		}
+		if got := tracker.Reserve(tt.estimate); got != tt.want {
+			t.Errorf("%s: Reserve(%v) = %v, want %v", tt.name, tt.estimate, got, tt.want)
+		}
+	}
+}
+
+func TestUsageTrackerRecordReleasesReservation(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.Local)
+	tracker := newTestUsageTracker(0.10, &now)
+
+	if !tracker.Reserve(0.06) {
+		t.Fatal("見積もりを確保できません")
+	}
+	if tracker.Reserve(0.06) {
+		t.Fatal("確保中の額を含めると上限を超えるのに確保しました")
+	}
+	// 実際の利用額が見積もりより少なければ、差額を次の呼び出しに使える
+	tracker.Record("a", 0.06, entity.TokenUsage{EstimatedCostUSD: 0.01})
+	if !tracker.Reserve(0.06) {
+		t.Error("確保を解放した後に見積もりを確保できません")
+	}
+}
+
+func TestUsageTrackerConcurrentReserveStaysWithinLimit(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.Local)
+	tracker := newTestUsageTracker(0.10, &now)
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		granted int
+	)
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if tracker.Reserve(0.01) {
+				mu.Lock()
+				granted++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if granted != 10 {
+		t.Errorf("確保できた回数 = %d, want 10", granted)
+	}
+}
+
+// almostEqual は浮動小数点の誤差を許して比較します
+func almostEqual(a, b float64) bool {
+	const epsilon = 1e-9
+	return a-b < epsilon && b-a < epsilon
+}
@@ -0,0 +1,253 @@
+package api
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/unicode/norm"
+	"restaurant-finder/Domain/entity"
+)
+
+// kanjiNumbers は人数・時刻に使われる漢数字です
+var kanjiNumbers = map[string]int{
+	"一": 1, "二": 2, "三": 3, "四": 4, "五": 5, "六": 6, "七": 7, "八": 8, "九": 9, "十": 10,
+	"十一": 11, "十二": 12, "ひと": 1, "ふた": 2,
+}
+
+// mealWords は食事の種類を示す語です（先に一致したものを使います）
+var mealWords = []struct {
+	meal  string
+	words []string
+}{
+	{entity.MealLateNight, []string{"深夜", "夜中", "二次会", "三次会", "終電後", "late night"}},
+	{entity.MealLunch, []string{"ランチ", "昼食", "昼ごはん", "昼ご飯", "お昼", "lunch"}},
+	{entity.MealDrinks, []string{"飲み会", "宴会", "歓迎会", "送別会", "忘年会", "新年会", "打ち上げ", "女子会", "合コン", "飲み", "drinks"}},
+	{entity.MealDinner, []string{"ディナー", "夕食", "夕飯", "晩ごはん", "晩ご飯", "dinner"}},
+}
+
+// mealDefaultHours は時刻の指定がない場合に使う食事ごとの時刻です
+var mealDefaultHours = map[string]int{
+	entity.MealLunch:     12,
+	entity.MealDinner:    19,
+	entity.MealDrinks:    19,
+	entity.MealLateNight: 23,
+}
+
+var (
+	// partySizePattern は "4人" "4名" "四人" "ふたり" "4 people" などの人数です
+	partySizePattern = regexp.MustCompile(`(\d{1,3}|十[一二]?|[一二三四五六七八九十])\s*(?:人|名)|(ひと|ふた)り|(\d{1,3})\s*(?:people|persons|pax|guests)`)
+	// clockPattern は "20:00" 形式の時刻です
+	clockPattern = regexp.MustCompile(`(\d{1,2}):(\d{2})`)
+	// hourPattern は "8時" "午後8時半" "八時" などの時刻です
+	hourPattern = regexp.MustCompile(`(午前|午後|朝|昼|夕方|夜)?\s*(\d{1,2}|十[一二]?|[一二三四五六七八九十])\s*時\s*(半|(\d{1,2})\s*分)?`)
+	// englishHourPattern は "8pm" "8 pm" のような英語の時刻です
+	englishHourPattern = regexp.MustCompile(`(?i)\b(\d{1,2})(?::(\d{2}))?\s*(am|pm)\b`)
+	// monthDayPattern は "12月24日" "12/24" のような日付です
+	monthDayPattern = regexp.MustCompile(`(\d{1,2})\s*月\s*(\d{1,2})\s*日|\b(\d{1,2})/(\d{1,2})\b`)
+	// weekdayPattern は "来週金曜" "今週の土曜日" "金曜" のような曜日の指定です
+	weekdayPattern = regexp.MustCompile(`(今週|来週|再来週)?の?([月火水木金土日])曜`)
+)
+
+// ExtractVisitIntent はクエリから来店の日時・人数・食事の種類を読み取ります
+// 相対的な日付（今夜・明日・来週金曜など）は now を基準に解決します
+func ExtractVisitIntent(text string, now time.Time) entity.VisitIntent {
+	text = norm.NFKC.String(text)
+	lower := strings.ToLower(text)
+	now = now.In(entity.JST)
+
+	intent := entity.VisitIntent{PartySize: extractPartySize(text)}
+	for _, mw := range mealWords {
+		for _, word := range mw.words {
+			if strings.Contains(lower, word) {
+				intent.Meal = mw.meal
+				break
+			}
+		}
+		if intent.Meal != "" {
+			break
+		}
+	}
+
+	evening := containsAny(lower, "今夜", "今晩", "夜", "夕方", "晩", "tonight", "evening")
+	date, hasDate := extractDate(lower, now)
+	if intent.Meal == "" && evening && hasDate {
+		// "今夜" "明日の夜" のように食事の種類がなければ夕食とみなす
+		intent.Meal = entity.MealDinner
+	}
+	hour, minute, hasTime := extractTime(lower, evening || intent.Meal == entity.MealDinner || intent.Meal == entity.MealDrinks)
+	if !hasTime && intent.Meal != "" && (hasDate || evening) {
+		hour, hasTime = mealDefaultHours[intent.Meal], true
+	}
+	if !hasDate && !hasTime {
+		return intent
+	}
+	if !hasDate {
+		date = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, entity.JST)
+	}
+	if !hasTime {
+		// 日付だけの指定は営業時間で絞り込まない
+		return intent
+	}
+
+	at := date.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	intent.At = &at
+	if intent.Meal == "" && (hour >= 23 || hour < 5) {
+		intent.Meal = entity.MealLateNight
+	}
+	return intent
+}
+
+// extractPartySize は人数を読み取ります
+func extractPartySize(text string) int {
+	if strings.Contains(text, "おひとり様") || strings.Contains(text, "一人で") || strings.Contains(text, "ひとりで") {
+		return 1
+	}
+	m := partySizePattern.FindStringSubmatch(text)
+	if m == nil {
+		return 0
+	}
+	for _, s := range []string{m[1], m[2], m[3]} {
+		if s == "" {
+			continue
+		}
+		if n, ok := parseSmallNumber(s); ok {
+			return n
+		}
+	}
+	return 0
+}
+
+// extractDate は日付の指定を読み取ります（時刻は 0:00）
+func extractDate(text string, now time.Time) (time.Time, bool) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, entity.JST)
+
+	switch {
+	case containsAny(text, "明後日", "あさって"):
+		return today.AddDate(0, 0, 2), true
+	case containsAny(text, "明日", "あした", "あす", "tomorrow"):
+		return today.AddDate(0, 0, 1), true
+	case containsAny(text, "今日", "本日", "今夜", "今晩", "today", "tonight"):
+		return today, true
+	}
+
+	if m := monthDayPattern.FindStringSubmatch(text); m != nil {
+		month, day := m[1], m[2]
+		if month == "" {
+			month, day = m[3], m[4]
+		}
+		mo, _ := strconv.Atoi(month)
+		d, _ := strconv.Atoi(day)
+		if mo >= 1 && mo <= 12 && d >= 1 && d <= 31 {
+			date := time.Date(today.Year(), time.Month(mo), d, 0, 0, 0, 0, entity.JST)
+			if date.Before(today) {
+				date = date.AddDate(1, 0, 0)
+			}
+			return date, true
+		}
+	}
+
+	if m := weekdayPattern.FindStringSubmatch(text); m != nil {
+		target := weekdayNames[m[2]]
+		// 週は月曜日始まりとする
+		weekStart := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+		offset := (int(target) + 6) % 7
+		switch m[1] {
+		case "今週":
+			return weekStart.AddDate(0, 0, offset), true
+		case "来週":
+			return weekStart.AddDate(0, 0, 7+offset), true
+		case "再来週":
+			return weekStart.AddDate(0, 0, 14+offset), true
+		}
+		// 週の指定がない場合は今日以降で最も近いその曜日
+		return today.AddDate(0, 0, (int(target)-int(today.Weekday())+7)%7), true
+	}
+
+	if containsAny(text, "週末", "weekend") {
+		return today.AddDate(0, 0, (int(time.Saturday)-int(today.Weekday())+7)%7), true
+	}
+	return time.Time{}, false
+}
+
+// extractTime は時刻を読み取ります。evening が true の場合、12 時より前の時刻は夜（午後）とみなします
+func extractTime(text string, evening bool) (hour, minute int, ok bool) {
+	// "24時間営業" "3時間飲み放題" などの時間の長さは時刻として扱わない
+	text = strings.ReplaceAll(text, "時間", " ")
+	if m := clockPattern.FindStringSubmatch(text); m != nil {
+		hour, _ = strconv.Atoi(m[1])
+		minute, _ = strconv.Atoi(m[2])
+		return normalizeHour(hour, minute, "", evening)
+	}
+	if m := englishHourPattern.FindStringSubmatch(text); m != nil {
+		hour, _ = strconv.Atoi(m[1])
+		minute, _ = strconv.Atoi(m[2])
+		if strings.EqualFold(m[3], "pm") && hour < 12 {
+			hour += 12
+		}
+		if strings.EqualFold(m[3], "am") && hour == 12 {
+			hour = 0
+		}
+		return hour, minute, hour < 24 && minute < 60
+	}
+	if m := hourPattern.FindStringSubmatch(text); m != nil {
+		h, valid := parseSmallNumber(m[2])
+		if !valid {
+			return 0, 0, false
+		}
+		switch {
+		case m[3] == "半":
+			minute = 30
+		case m[4] != "":
+			minute, _ = strconv.Atoi(m[4])
+		}
+		return normalizeHour(h, minute, m[1], evening)
+	}
+	return 0, 0, false
+}
+
+// normalizeHour は午前・午後の指定や文脈から 24 時間制の時刻に変換します
+// 飲食店の検索のため、午前の指定がなければ 1〜10 時は夜の時刻とみなします
+func normalizeHour(hour, minute int, period string, evening bool) (int, int, bool) {
+	if hour > 29 || minute > 59 {
+		return 0, 0, false
+	}
+	switch period {
+	case "午前", "朝":
+		if hour == 12 {
+			hour = 0
+		}
+	case "午後", "夕方", "夜":
+		if hour < 12 {
+			hour += 12
+		}
+	default:
+		if hour >= 1 && hour <= 10 || evening && hour < 12 {
+			hour += 12
+		}
+	}
+	if hour >= 24 {
+		hour -= 24
+	}
+	return hour, minute, true
+}
+
+// parseSmallNumber は算用数字・漢数字の小さな数を変換します
+func parseSmallNumber(s string) (int, bool) {
+	if n, err := strconv.Atoi(s); err == nil {
+		return n, true
+	}
+	n, ok := kanjiNumbers[s]
+	return n, ok
+}
+
+// containsAny は文字列がいずれかの語を含むかを返します
+func containsAny(s string, words ...string) bool {
+	for _, w := range words {
+		if strings.Contains(s, w) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/text/unicode/norm"
+	"restaurant-finder/Domain/entity"
+)
+
+func TestExtractVisitIntent(t *testing.T) {
+	// 2025-06-04 は水曜日
+	now := time.Date(2025, 6, 4, 15, 0, 0, 0, entity.JST)
+	tests := []struct {
+		text  string
+		at    string // "2006-01-02 15:04"（日時を読み取らない場合は空）
+		party int
+		meal  string
+	}{
+		{"明日の夜 渋谷 4人 飲み会", "2025-06-05 19:00", 4, entity.MealDrinks},
+		{"今夜8時 新宿 ふたり", "2025-06-04 20:00", 2, entity.MealDinner},
+		{"来週金曜 19:30 六人 歓迎会", "2025-06-13 19:30", 6, entity.MealDrinks},
+		{"12月24日 ディナー 2名", "2025-12-24 19:00", 2, entity.MealDinner},
+		{"5/1 ランチ", "2026-05-01 12:00", 0, entity.MealLunch},
+		{"tonight 8pm izakaya for 3 people", "2025-06-04 20:00", 3, entity.MealDinner},
+		{"今日23時", "2025-06-04 23:00", 0, entity.MealLateNight},
+		{"明日 午前11時半", "2025-06-05 11:30", 0, ""},
+		{"ランチ 銀座", "", 0, entity.MealLunch},
+		{"3時間飲み放題 10人", "", 10, entity.MealDrinks},
+		{"終電後 二次会", "", 0, entity.MealLateNight},
+		{"週末 焼肉", "", 0, ""},
+		{"おひとり様 ラーメン", "", 1, ""},
+		{"渋谷 居酒屋", "", 0, ""},
+	}
+	for _, tt := range tests {
+		got := ExtractVisitIntent(tt.text, now)
+		at := ""
+		if got.At != nil {
+			at = got.At.In(entity.JST).Format("2006-01-02 15:04")
+		}
+		if at != tt.at || got.PartySize != tt.party || got.Meal != tt.meal {
+			t.Errorf("ExtractVisitIntent(%q) = {At: %q, PartySize: %d, Meal: %q}, want {At: %q, PartySize: %d, Meal: %q}",
+				tt.text, at, got.PartySize, got.Meal, tt.at, tt.party, tt.meal)
+		}
+	}
+}
+
+func TestExtractPartySize(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"4人", 4},
+		{"12名", 12},
+		{"４人", 4},
+		{"十人", 10},
+		{"十二名", 12},
+		{"四人", 4},
+		{"ふたり", 2},
+		{"ひとりで", 1},
+		{"一人で飲める店", 1},
+		{"おひとり様", 1},
+		{"6 people", 6},
+		{"for 8 guests", 8},
+		{"渋谷 居酒屋", 0},
+		{"3時間飲み放題", 0},
+	}
+	for _, tt := range tests {
+		if got := extractPartySize(norm.NFKC.String(tt.text)); got != tt.want {
+			t.Errorf("extractPartySize(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
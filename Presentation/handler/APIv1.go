@@ -0,0 +1,391 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"restaurant-finder/Application/usecase"
+	"restaurant-finder/Domain/entity"
+)
+
+// APIV1Prefix は JSON API のパスの接頭辞です
+const APIV1Prefix = "/api/v1"
+
+// エラーレスポンスの code
+const (
+	apiErrorInvalidRequest = "invalid_request"
+	apiErrorInputRejected  = "input_rejected"
+	apiErrorNotFound       = "not_found"
+	apiErrorInternal       = "internal_error"
+)
+
+// apiMaxCount は 1 回の検索で返す店舗の最大件数です
+const apiMaxCount = 100
+
+// APIRoute は JSON API のルートです。OpenAPI ドキュメントもこの一覧から生成します
+type APIRoute struct {
+	Method  string
+	Path    string
+	Handler gin.HandlerFunc
+	Summary string
+	Params  []APIParam
+	// Request / Response はリクエストボディとレスポンスの型の値です（ドキュメントの生成に使います）
+	Request  any
+	Response any
+	// Errors はこのルートが返すエラーの HTTP ステータスです
+	Errors []int
+}
+
+// APIParam はパスまたはクエリのパラメータです
+type APIParam struct {
+	Name        string
+	In          string
+	Description string
+	Required    bool
+	Enum        []string
+}
+
+// apiErrorResponse はすべてのエンドポイントで共通のエラーレスポンスです
+type apiErrorResponse struct {
+	Error apiError `json:"error"`
+}
+
+// apiError はエラーの種類（code）と説明（message）です
+type apiError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// apiSearchRequest は検索 API のリクエストです。query と params のどちらかを指定します
+type apiSearchRequest struct {
+	// Query は自然文の検索クエリです
+	Query string `json:"query,omitempty"`
+	// Params を指定した場合はクエリを解釈せず、この条件で検索します
+	Params *apiSearchParams `json:"params,omitempty"`
+	// Overrides は曖昧さの確認で選択したコードです（キーは "large_area" / "middle_area" / "small_area" / "genre"）
+	Overrides map[string]string `json:"overrides,omitempty"`
+	OpenNow   bool              `json:"open_now,omitempty"`
+	OpenAt    *time.Time        `json:"open_at,omitempty"`
+	Lat       float64           `json:"lat,omitempty"`
+	Lng       float64           `json:"lng,omitempty"`
+	// MeetStations は中間地点で探す場合の集合駅です
+	MeetStations   []string `json:"meet_stations,omitempty"`
+	MaxWalkMinutes int      `json:"max_walk_minutes,omitempty"`
+	// Sort は並び順です（"walk" は駅から近い順）
+	Sort string `json:"sort,omitempty"`
+}
+
+// apiSearchParams は API で指定・返却する検索条件です
+// 提供元へのリクエストの項目（API キーなど）は含めず、サーバーで entity.SearchCriteria に変換します
+type apiSearchParams struct {
+	Keyword string `json:"keyword,omitempty"`
+	// Lat / Lng を指定した場合は、その位置から RadiusMeters 以内の店舗を検索します
+	Lat          float64 `json:"lat,omitempty"`
+	Lng          float64 `json:"lng,omitempty"`
+	RadiusMeters int     `json:"radius_meters,omitempty"`
+	LargeArea    string  `json:"large_area,omitempty"`
+	MiddleArea   string  `json:"middle_area,omitempty"`
+	SmallArea    string  `json:"small_area,omitempty"`
+	Genre        string  `json:"genre,omitempty"`
+	Budget       string  `json:"budget,omitempty"`
+	// Facilities は必須の設備・サービスです（"lunch" / "private_room" など）
+	Facilities    []string `json:"facilities,omitempty"`
+	PartyCapacity int      `json:"party_capacity,omitempty"`
+	// Start（1 から）と Count（1〜100、既定は 10）で返すページを指定します
+	Start int `json:"start,omitempty"`
+	Count int `json:"count,omitempty"`
+}
+
+// criteria は検索条件を検証して entity.SearchCriteria に変換します
+func (p *apiSearchParams) criteria() (*entity.SearchCriteria, error) {
+	switch {
+	case p.Count < 0 || p.Count > apiMaxCount:
+		return nil, fmt.Errorf("count には 1〜%d を指定してください", apiMaxCount)
+	case p.Start < 0:
+		return nil, errors.New("start には 1 以上を指定してください")
+	case p.RadiusMeters < 0:
+		return nil, errors.New("radius_meters には 0 以上を指定してください")
+	case p.PartyCapacity < 0:
+		return nil, errors.New("party_capacity には 0 以上を指定してください")
+	}
+	for _, facility := range p.Facilities {
+		if !entity.IsFacility(facility) {
+			return nil, fmt.Errorf("facilities に指定できない値です: %s", facility)
+		}
+	}
+	criteria := &entity.SearchCriteria{
+		Keyword:       p.Keyword,
+		Lat:           p.Lat,
+		Lng:           p.Lng,
+		RadiusMeters:  p.RadiusMeters,
+		LargeArea:     p.LargeArea,
+		MiddleArea:    p.MiddleArea,
+		SmallArea:     p.SmallArea,
+		Genre:         p.Genre,
+		Budget:        p.Budget,
+		PartyCapacity: p.PartyCapacity,
+		Start:         p.Start,
+		Count:         p.Count,
+	}
+	for _, facility := range p.Facilities {
+		criteria.SetFacility(facility, true)
+	}
+	return criteria, nil
+}
+
+// newAPISearchParams は検索に使った条件をレスポンスの形にします
+func newAPISearchParams(criteria *entity.SearchCriteria) *apiSearchParams {
+	if criteria == nil {
+		return nil
+	}
+	return &apiSearchParams{
+		Keyword:       criteria.Keyword,
+		Lat:           criteria.Lat,
+		Lng:           criteria.Lng,
+		RadiusMeters:  criteria.RadiusMeters,
+		LargeArea:     criteria.LargeArea,
+		MiddleArea:    criteria.MiddleArea,
+		SmallArea:     criteria.SmallArea,
+		Genre:         criteria.Genre,
+		Budget:        criteria.Budget,
+		Facilities:    criteria.Facilities,
+		PartyCapacity: criteria.PartyCapacity,
+		Start:         criteria.Start,
+		Count:         criteria.Count,
+	}
+}
+
+// apiSearchResponse は検索 API のレスポンスです
+type apiSearchResponse struct {
+	Query              string              `json:"query"`
+	SearchParams       *apiSearchParams    `json:"search_params"`
+	Language           string              `json:"language"`
+	ExtractionTier     string              `json:"extraction_tier"`
+	NeedsClarification bool                `json:"needs_clarification"`
+	Clarifications     []entity.Ambiguity  `json:"clarifications,omitempty"`
+	Ambiguities        []entity.Ambiguity  `json:"ambiguities,omitempty"`
+	Relaxations        []entity.Relaxation `json:"relaxations,omitempty"`
+	ResultsAvailable   int                 `json:"results_available"`
+	ResultsReturned    int                 `json:"results_returned"`
+	ResultsStart       int                 `json:"results_start"`
+	Shops              []entity.Restaurant `json:"shops"`
+	NaturalDescription string              `json:"natural_description,omitempty"`
+	Facets             []entity.Facet      `json:"facets,omitempty"`
+	Origin             string              `json:"origin,omitempty"`
+	Distances          map[string]int      `json:"distances,omitempty"`
+	UnknownHours       int                 `json:"unknown_hours,omitempty"`
+	TokenUsage         entity.TokenUsage   `json:"token_usage"`
+}
+
+// apiShopResponse は店舗 API のレスポンスです
+type apiShopResponse struct {
+	Shop *entity.Restaurant `json:"shop"`
+}
+
+// apiMasterResponse はマスタデータ API のレスポンスです
+type apiMasterResponse struct {
+	Items []entity.MasterOption `json:"items"`
+}
+
+// APIHandler JSON API（/api/v1）のハンドラ
+type APIHandler struct {
+	search     *usecase.GetRestaurantUsecase
+	masterData *usecase.MasterDataUsecase
+}
+
+// NewAPIHandler APIHandlerのコンストラクタ
+func NewAPIHandler(search *usecase.GetRestaurantUsecase, masterData *usecase.MasterDataUsecase) *APIHandler {
+	return &APIHandler{search: search, masterData: masterData}
+}
+
+// APIV1Routes は /api/v1 以下のルートの一覧です
+func APIV1Routes(h *APIHandler) []APIRoute {
+	return []APIRoute{
+		{
+			Method: http.MethodPost, Path: "/search", Handler: h.Search,
+			Summary: "自然文のクエリ、または検索条件でレストランを検索します",
+			Request: apiSearchRequest{}, Response: apiSearchResponse{},
+			Errors: []int{http.StatusBadRequest, http.StatusInternalServerError},
+		},
+		{
+			Method: http.MethodGet, Path: "/shops/:id", Handler: h.Shop,
+			Summary:  "店舗 ID で店舗を取得します",
+			Params:   []APIParam{{Name: "id", In: "path", Description: "店舗 ID", Required: true}},
+			Response: apiShopResponse{},
+			Errors:   []int{http.StatusNotFound, http.StatusInternalServerError},
+		},
+		{
+			Method: http.MethodGet, Path: "/areas", Handler: h.Areas,
+			Summary: "エリアの一覧を返します",
+			Params: []APIParam{
+				{Name: "level", In: "query", Description: "エリアの区分（既定は large）", Enum: []string{"large", "middle", "small"}},
+				{Name: "parent", In: "query", Description: "上位のエリアのコード（指定した場合はその中のエリアだけを返します）"},
+			},
+			Response: apiMasterResponse{},
+			Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+		},
+		{
+			Method: http.MethodGet, Path: "/genres", Handler: h.Genres,
+			Summary: "ジャンルの一覧を返します", Response: apiMasterResponse{},
+			Errors: []int{http.StatusInternalServerError},
+		},
+		{
+			Method: http.MethodGet, Path: "/budgets", Handler: h.Budgets,
+			Summary: "予算の一覧を返します", Response: apiMasterResponse{},
+			Errors: []int{http.StatusInternalServerError},
+		},
+		{
+			Method: http.MethodGet, Path: "/openapi.json", Handler: h.OpenAPI,
+			Summary: "この API の OpenAPI ドキュメントを返します", Response: map[string]any{},
+		},
+	}
+}
+
+// Search 検索条件または自然文のクエリでレストランを検索し、JSON で返す
+func (h *APIHandler) Search(c *gin.Context) {
+	var req apiSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apiErrorJSON(c, http.StatusBadRequest, apiErrorInvalidRequest, "リクエストの JSON が不正です: "+err.Error())
+		return
+	}
+	req.Query = strings.TrimSpace(req.Query)
+	if req.Query == "" && req.Params == nil {
+		apiErrorJSON(c, http.StatusBadRequest, apiErrorInvalidRequest, "query または params を指定してください")
+		return
+	}
+	if req.Sort != "" && req.Sort != "walk" {
+		apiErrorJSON(c, http.StatusBadRequest, apiErrorInvalidRequest, "sort には walk のみ指定できます")
+		return
+	}
+	var params *entity.SearchCriteria
+	if req.Params != nil {
+		var err error
+		if params, err = req.Params.criteria(); err != nil {
+			apiErrorJSON(c, http.StatusBadRequest, apiErrorInvalidRequest, err.Error())
+			return
+		}
+	}
+	openAt := req.OpenAt
+	if req.OpenNow {
+		now := time.Now().In(entity.JST)
+		openAt = &now
+	}
+
+	result, err := h.search.Search(usecase.SearchRequest{
+		Prompt:         req.Query,
+		ClientID:       c.ClientIP(),
+		Overrides:      req.Overrides,
+		Params:         params,
+		OpenAt:         openAt,
+		Lat:            req.Lat,
+		Lng:            req.Lng,
+		MeetStations:   req.MeetStations,
+		MaxWalkMinutes: req.MaxWalkMinutes,
+		SortByWalk:     req.Sort == "walk",
+	})
+	var rejected *usecase.InputRejectedError
+	if errors.As(err, &rejected) {
+		apiErrorJSON(c, http.StatusBadRequest, apiErrorInputRejected, rejected.Message)
+		return
+	}
+	if err != nil {
+		apiErrorJSON(c, http.StatusInternalServerError, apiErrorInternal, "検索中にエラーが発生しました: "+err.Error())
+		return
+	}
+
+	response := apiSearchResponse{
+		Query:              req.Query,
+		SearchParams:       newAPISearchParams(result.SearchParams),
+		Language:           result.Language,
+		ExtractionTier:     result.ExtractionTier,
+		NeedsClarification: result.NeedsClarification,
+		Clarifications:     result.Clarifications,
+		Ambiguities:        result.Ambiguities,
+		Relaxations:        result.Relaxations,
+		Shops:              []entity.Restaurant{},
+		NaturalDescription: result.NaturalDescription,
+		Facets:             result.Facets,
+		Origin:             result.Origin,
+		Distances:          result.Distances,
+		UnknownHours:       result.UnknownHours,
+		TokenUsage:         result.TokenUsage,
+	}
+	if result.Page != nil {
+		response.Shops = append(response.Shops, result.Page.Restaurants...)
+		response.ResultsAvailable = result.Page.Available
+		response.ResultsReturned = result.Page.Returned()
+		response.ResultsStart = result.Page.Start
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// Shop 店舗 ID で店舗を取得し、JSON で返す
+func (h *APIHandler) Shop(c *gin.Context) {
+	shop, err := h.search.GetShop(c.Param("id"))
+	if errors.Is(err, usecase.ErrShopNotFound) {
+		apiErrorJSON(c, http.StatusNotFound, apiErrorNotFound, "店舗が見つかりません: "+c.Param("id"))
+		return
+	}
+	if err != nil {
+		apiErrorJSON(c, http.StatusInternalServerError, apiErrorInternal, "店舗の取得中にエラーが発生しました: "+err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, apiShopResponse{Shop: shop})
+}
+
+// Areas エリアの一覧を JSON で返す
+func (h *APIHandler) Areas(c *gin.Context) {
+	items, err := h.masterData.Areas(c.Query("level"), c.Query("parent"))
+	if errors.Is(err, usecase.ErrUnknownAreaLevel) {
+		apiErrorJSON(c, http.StatusBadRequest, apiErrorInvalidRequest, err.Error())
+		return
+	}
+	masterJSON(c, items, err)
+}
+
+// Genres ジャンルの一覧を JSON で返す
+func (h *APIHandler) Genres(c *gin.Context) {
+	items, err := h.masterData.Genres()
+	masterJSON(c, items, err)
+}
+
+// Budgets 予算の一覧を JSON で返す
+func (h *APIHandler) Budgets(c *gin.Context) {
+	items, err := h.masterData.Budgets()
+	masterJSON(c, items, err)
+}
+
+// OpenAPI OpenAPI ドキュメントを返す
+func (h *APIHandler) OpenAPI(c *gin.Context) {
+	c.JSON(http.StatusOK, OpenAPIDocument())
+}
+
+// NotFoundHandler 存在しないパスへのリクエストに 404 を返す（/api/ 以下は JSON のエラーレスポンス）
+func NotFoundHandler(c *gin.Context) {
+	if strings.HasPrefix(c.Request.URL.Path, "/api/") {
+		apiErrorJSON(c, http.StatusNotFound, apiErrorNotFound, "エンドポイントが見つかりません: "+c.Request.URL.Path)
+		return
+	}
+	c.String(http.StatusNotFound, "404 page not found")
+}
+
+// masterJSON はマスタデータの一覧を JSON で返す
+func masterJSON(c *gin.Context, items []entity.MasterOption, err error) {
+	if err != nil {
+		apiErrorJSON(c, http.StatusInternalServerError, apiErrorInternal, "マスタデータを読み込めません: "+err.Error())
+		return
+	}
+	if items == nil {
+		items = []entity.MasterOption{}
+	}
+	c.JSON(http.StatusOK, apiMasterResponse{Items: items})
+}
+
+// apiErrorJSON は共通の形式でエラーを返す
+func apiErrorJSON(c *gin.Context, status int, code, message string) {
+	c.AbortWithStatusJSON(status, apiErrorResponse{Error: apiError{Code: code, Message: message}})
+}
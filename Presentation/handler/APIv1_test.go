@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"restaurant-finder/Application/usecase"
+	"restaurant-finder/Domain/entity"
+)
+
+// fakeRestaurantSearcher は固定の店舗を返す検索のフェイクです
+type fakeRestaurantSearcher struct {
+	calls []entity.SearchCriteria
+}
+
+func (f *fakeRestaurantSearcher) SearchRestaurants(criteria *entity.SearchCriteria) (*entity.RestaurantPage, error) {
+	f.calls = append(f.calls, *criteria)
+	shops := []entity.Restaurant{
+		{ID: "J1", Name: "近い店", Lat: 35.6897, Lng: 139.7007},
+		{ID: "J2", Name: "遠い店", Lat: 35.6950, Lng: 139.7050},
+	}
+	return &entity.RestaurantPage{Restaurants: shops, Available: len(shops), Start: 1}, nil
+}
+
+// fakeMasterDataRepository は空のマスタデータを返すフェイクです
+type fakeMasterDataRepository struct{}
+
+func (fakeMasterDataRepository) MasterData() (*entity.MasterData, error) {
+	return &entity.MasterData{}, nil
+}
+
+// postSearch は検索 API に JSON を送り、レスポンスを返します
+func postSearch(t *testing.T, searcher *fakeRestaurantSearcher, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	search := usecase.NewGetRestaurantUsecase(searcher, nil, nil, nil, nil, fakeMasterDataRepository{}, nil)
+	router := gin.New()
+	router.POST(APIV1Prefix+"/search", NewAPIHandler(search, nil).Search)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, APIV1Prefix+"/search", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAPISearchRejectsInvalidParams(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"負の件数", `{"params": {"lat": 35.6896, "lng": 139.7006, "count": -5}}`},
+		{"上限を超える件数", `{"params": {"keyword": "居酒屋", "count": 101}}`},
+		{"負の開始位置", `{"params": {"keyword": "居酒屋", "start": -1}}`},
+		{"一覧にない設備", `{"params": {"keyword": "居酒屋", "facilities": ["key"]}}`},
+	}
+	for _, tt := range tests {
+		searcher := &fakeRestaurantSearcher{}
+		w := postSearch(t, searcher, tt.body)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400: %s", tt.name, w.Code, w.Body.String())
+			continue
+		}
+		var resp apiErrorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || resp.Error.Code != apiErrorInvalidRequest {
+			t.Errorf("%s: error = %+v (%v)", tt.name, resp.Error, err)
+		}
+		if len(searcher.calls) != 0 {
+			t.Errorf("%s: 不正な条件で検索しました: %+v", tt.name, searcher.calls)
+		}
+	}
+}
+
+func TestAPISearchWithParams(t *testing.T) {
+	searcher := &fakeRestaurantSearcher{}
+	w := postSearch(t, searcher, `{"params": {"lat": 35.6896, "lng": 139.7006, "count": 1, "facilities": ["lunch"]}}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	var resp apiSearchResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Shops) != 1 || resp.Shops[0].ID != "J1" {
+		t.Errorf("shops = %+v", resp.Shops)
+	}
+	if resp.SearchParams == nil || resp.SearchParams.RadiusMeters != entity.DefaultRadiusMeters {
+		t.Errorf("search_params = %+v", resp.SearchParams)
+	}
+	if len(searcher.calls) == 0 || !searcher.calls[0].RequiresFacility(entity.FacilityLunch) {
+		t.Errorf("calls = %+v", searcher.calls)
+	}
+	// 提供元へのリクエストの項目はレスポンスに含めない
+	for _, key := range []string{`"key"`, `"format"`} {
+		if strings.Contains(w.Body.String(), key) {
+			t.Errorf("レスポンスに %s が含まれています", key)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"restaurant-finder/Application/usecase"
+	"restaurant-finder/Domain/entity"
+)
+
+// ErrAdminCredentialsMissing は管理ページの認証情報が設定されていないことを示すエラーです
+var ErrAdminCredentialsMissing = errors.New("ADMIN_USER / ADMIN_PASSWORD が未設定のため管理ページは無効です")
+
+// csrfFormField は管理ページのフォームで CSRF トークンを送る項目名です
+const csrfFormField = "csrf_token"
+
+// AdminHandler 同義語辞書の管理ページのハンドラ
+type AdminHandler struct {
+	synonyms *usecase.SynonymUsecase
+	// csrfSecret は CSRF トークンの署名に使う鍵です（起動ごとに作成します）
+	csrfSecret []byte
+}
+
+// NewAdminHandler AdminHandlerのコンストラクタ
+func NewAdminHandler(synonyms *usecase.SynonymUsecase) *AdminHandler {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		panic("CSRF トークンの鍵を作成できません: " + err.Error())
+	}
+	return &AdminHandler{synonyms: synonyms, csrfSecret: secret}
+}
+
+// RegisterAdminRoutes 管理ページのルートを Basic 認証と CSRF の検証付きで登録する
+// 認証情報が設定されていない場合は登録せず ErrAdminCredentialsMissing を返す
+func RegisterAdminRoutes(router gin.IRouter, h *AdminHandler, user, password string) error {
+	if user == "" || password == "" {
+		return ErrAdminCredentialsMissing
+	}
+	admin := router.Group("/admin", gin.BasicAuth(gin.Accounts{user: password}), h.verifyCSRF)
+	admin.GET("/synonyms", h.Synonyms)
+	admin.POST("/synonyms", h.SaveSynonym)
+	admin.POST("/synonyms/delete", h.DeleteSynonym)
+	return nil
+}
+
+// Synonyms 同義語辞書の管理ページを表示
+func (h *AdminHandler) Synonyms(c *gin.Context) {
+	h.renderSynonymAdmin(c, http.StatusOK, "", "")
+}
+
+// SaveSynonym 同義語を登録して管理ページを表示
+func (h *AdminHandler) SaveSynonym(c *gin.Context) {
+	syn := entity.Synonym{
+		Alias:   c.PostForm("alias"),
+		Field:   c.PostForm("field"),
+		Code:    c.PostForm("code"),
+		Name:    c.PostForm("name"),
+		Keyword: c.PostForm("keyword"),
+	}
+	if err := h.synonyms.SaveSynonym(syn); err != nil {
+		h.renderSynonymAdmin(c, http.StatusBadRequest, "", err.Error())
+		return
+	}
+	h.renderSynonymAdmin(c, http.StatusOK, "「"+syn.Alias+"」を登録しました", "")
+}
+
+// DeleteSynonym 同義語を削除して管理ページを表示
+func (h *AdminHandler) DeleteSynonym(c *gin.Context) {
+	alias := c.PostForm("alias")
+	if err := h.synonyms.DeleteSynonym(alias); err != nil {
+		h.renderSynonymAdmin(c, http.StatusBadRequest, "", err.Error())
+		return
+	}
+	h.renderSynonymAdmin(c, http.StatusOK, "「"+alias+"」を削除しました", "")
+}
+
+// renderSynonymAdmin 同義語の一覧とメッセージを管理ページに渡す
+func (h *AdminHandler) renderSynonymAdmin(c *gin.Context, status int, message, errMessage string) {
+	c.HTML(status, "synonyms.html", gin.H{
+		"synonyms":  h.synonyms.ListSynonyms(),
+		"message":   message,
+		"error":     errMessage,
+		"csrfToken": h.csrfToken(c),
+	})
+}
+
+// csrfToken ログイン中の管理者に対応する CSRF トークンを返す
+// 鍵を知らない他のサイトからはトークンを作れないため、フォームの送信元が管理ページであることを確認できる
+func (h *AdminHandler) csrfToken(c *gin.Context) string {
+	mac := hmac.New(sha256.New, h.csrfSecret)
+	mac.Write([]byte(c.GetString(gin.AuthUserKey)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// verifyCSRF POST の場合にフォームの CSRF トークンを検証する
+func (h *AdminHandler) verifyCSRF(c *gin.Context) {
+	if c.Request.Method != http.MethodPost {
+		c.Next()
+		return
+	}
+	if !hmac.Equal([]byte(c.PostForm(csrfFormField)), []byte(h.csrfToken(c))) {
+		h.renderSynonymAdmin(c, http.StatusForbidden, "", "フォームの有効期限が切れています。ページを再読み込みしてからやり直してください")
+		c.Abort()
+		return
+	}
+	c.Next()
+}
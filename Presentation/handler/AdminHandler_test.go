@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"restaurant-finder/Application/usecase"
+	"restaurant-finder/Domain/entity"
+)
+
+// fakeSynonymRepository はメモリ上の同義語辞書のフェイクです
+type fakeSynonymRepository struct {
+	entries []entity.Synonym
+}
+
+func (f *fakeSynonymRepository) List() []entity.Synonym { return f.entries }
+
+func (f *fakeSynonymRepository) Add(syn entity.Synonym) error {
+	f.entries = append(f.entries, syn)
+	return nil
+}
+
+func (f *fakeSynonymRepository) Remove(alias string) error {
+	return errors.New("未対応")
+}
+
+// newAdminRouter は管理ページだけを登録したルーターを作成します
+func newAdminRouter(t *testing.T, repo *fakeSynonymRepository) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.LoadHTMLGlob("../../templates/*.html")
+	if err := RegisterAdminRoutes(router, NewAdminHandler(usecase.NewSynonymUsecase(repo)), "admin", "secret"); err != nil {
+		t.Fatal(err)
+	}
+	return router
+}
+
+func TestRegisterAdminRoutesRequiresCredentials(t *testing.T) {
+	router := gin.New()
+	h := NewAdminHandler(usecase.NewSynonymUsecase(&fakeSynonymRepository{}))
+	for _, creds := range [][2]string{{"", ""}, {"admin", ""}, {"", "secret"}} {
+		if err := RegisterAdminRoutes(router, h, creds[0], creds[1]); !errors.Is(err, ErrAdminCredentialsMissing) {
+			t.Errorf("RegisterAdminRoutes(%q, %q) = %v", creds[0], creds[1], err)
+		}
+	}
+	if n := len(router.Routes()); n != 0 {
+		t.Errorf("認証情報がないのに %d 件のルートが登録されました", n)
+	}
+}
+
+func TestAdminRequiresBasicAuth(t *testing.T) {
+	router := newAdminRouter(t, &fakeSynonymRepository{})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/synonyms", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestAdminPostRequiresCSRFToken(t *testing.T) {
+	repo := &fakeSynonymRepository{}
+	router := newAdminRouter(t, repo)
+
+	// 管理ページを表示してフォームのトークンを取り出す
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/admin/synonyms", nil)
+	req.SetBasicAuth("admin", "secret")
+	router.ServeHTTP(w, req)
+	match := regexp.MustCompile(`name="csrf_token" value="([0-9a-f]+)"`).FindStringSubmatch(w.Body.String())
+	if w.Code != http.StatusOK || match == nil {
+		t.Fatalf("status = %d, トークンがフォームにありません", w.Code)
+	}
+
+	post := func(token string) int {
+		form := url.Values{"alias": {"ハチ公前"}, "field": {"location"}, "name": {"渋谷"}}
+		if token != "" {
+			form.Set("csrf_token", token)
+		}
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/admin/synonyms", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.SetBasicAuth("admin", "secret")
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	for _, token := range []string{"", "0123abcd"} {
+		if code := post(token); code != http.StatusForbidden {
+			t.Errorf("token=%q: status = %d, want %d", token, code, http.StatusForbidden)
+		}
+	}
+	if len(repo.entries) != 0 {
+		t.Fatal("トークンが不正なのに同義語が登録されました")
+	}
+	if code := post(match[1]); code != http.StatusOK {
+		t.Errorf("status = %d, want %d", code, http.StatusOK)
+	}
+	if len(repo.entries) != 1 {
+		t.Errorf("同義語が登録されていません: %+v", repo.entries)
+	}
+}
@@ -0,0 +1,231 @@
+package handler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"restaurant-finder/Domain/entity"
+)
+
+// selectChip はプルダウンで変更できる検索条件（エリア・ジャンル・予算）です
+type selectChip struct {
+	Field   string
+	Label   string
+	Value   string
+	Name    string
+	Options []entity.MasterOption
+}
+
+// rangeOption は現在地からの検索範囲の選択肢です
+type rangeOption struct {
+	Code  int
+	Label string
+}
+
+// rangeOptions は HotPepper API の range コードの選択肢です
+var rangeOptions = []rangeOption{
+	{Code: 1, Label: "300m"},
+	{Code: 2, Label: "500m"},
+	{Code: 3, Label: "1km"},
+	{Code: 4, Label: "2km"},
+	{Code: 5, Label: "3km"},
+}
+
+// filterView は解釈された検索条件を編集するためのチップの一覧です
+type filterView struct {
+	Selects []selectChip
+	// Flags は指定されている条件、AvailableFlags は追加できる条件です
+	Flags          []entity.FacilityOption
+	AvailableFlags []entity.FacilityOption
+	Keyword        string
+	// PartyCapacity は宴会収容人数の条件です
+	PartyCapacity int
+	// OpenNow / OpenAt は営業時間での絞り込みです（OpenAt は datetime-local 形式）
+	OpenNow bool
+	OpenAt  string
+	// Lat / Lng / Range は現在地や駅からの検索範囲です（Lat / Lng は指定していない場合は空）
+	// Origin は距離の基準にした地点の名前です
+	Origin       string
+	Lat          string
+	Lng          string
+	Range        int
+	RangeOptions []rangeOption
+	// MaxWalkMinutes は駅からの徒歩の上限（分）、SortByWalk は駅から近い順に並べるかです
+	MaxWalkMinutes int
+	SortByWalk     bool
+}
+
+// openAtLayout は datetime-local 入力の形式です
+const openAtLayout = "2006-01-02T15:04"
+
+// openFilterFromForm はフォームから営業時間での絞り込み（「営業中」または日時の指定）を取り出す
+func openFilterFromForm(c *gin.Context) (at *time.Time, openNow bool, openAt string) {
+	remove := formValue(c, "remove")
+	if formValue(c, "open_now") == "1" && remove != "open_now" {
+		now := time.Now().In(entity.JST)
+		return &now, true, ""
+	}
+	if v := formValue(c, "open_at"); v != "" && remove != "open_at" {
+		if t, err := time.ParseInLocation(openAtLayout, v, entity.JST); err == nil {
+			return &t, false, v
+		}
+	}
+	return nil, false, ""
+}
+
+// locationFromForm はフォームからブラウザで取得した現在地を取り出す（未指定・不正な値の場合は 0, 0）
+func locationFromForm(c *gin.Context) (lat, lng float64) {
+	lat, errLat := strconv.ParseFloat(formValue(c, "lat"), 64)
+	lng, errLng := strconv.ParseFloat(formValue(c, "lng"), 64)
+	if errLat != nil || errLng != nil || !entity.ValidLatLng(lat, lng) {
+		return 0, 0
+	}
+	return lat, lng
+}
+
+// walkFilterFromForm はフォームから駅からの徒歩の上限と並び順を取り出す
+func walkFilterFromForm(c *gin.Context) (maxMinutes int, sortByWalk bool) {
+	if n, err := strconv.Atoi(formValue(c, "walk_minutes")); err == nil && n > 0 && formValue(c, "remove") != "walk_minutes" {
+		maxMinutes = n
+	}
+	return maxMinutes, formValue(c, "sort") == "walk"
+}
+
+// meetStationsFromForm はフォームから集合駅の一覧（カンマ・読点・空白区切り）を取り出す
+func meetStationsFromForm(c *gin.Context) []string {
+	return strings.FieldsFunc(formValue(c, "meet_stations"), func(r rune) bool {
+		return r == ',' || r == '、' || r == '，' || r == ' ' || r == '　'
+	})
+}
+
+// formatDistances は店舗 ID ごとの距離を表示用の文字列（"約350m" / "約1.2km"）にする
+func formatDistances(distances map[string]int) map[string]string {
+	formatted := make(map[string]string, len(distances))
+	for id, meters := range distances {
+		if meters < 1000 {
+			formatted[id] = fmt.Sprintf("約%dm", (meters+5)/10*10)
+		} else {
+			formatted[id] = fmt.Sprintf("約%.1fkm", float64(meters)/1000)
+		}
+	}
+	return formatted
+}
+
+// newFilterView は検索条件とマスタデータからチップの一覧を作成します
+func newFilterView(params *entity.SearchCriteria, master *entity.MasterData) *filterView {
+	if params == nil {
+		return nil
+	}
+
+	view := &filterView{Keyword: params.Keyword, PartyCapacity: params.PartyCapacity}
+	if entity.ValidLatLng(params.Lat, params.Lng) {
+		view.Lat = strconv.FormatFloat(params.Lat, 'f', -1, 64)
+		view.Lng = strconv.FormatFloat(params.Lng, 'f', -1, 64)
+		view.Range = entity.RangeForMeters(params.RadiusMeters)
+		view.RangeOptions = rangeOptions
+	}
+	view.Selects = append(view.Selects, selectChip{
+		Field: "large_area", Label: "エリア", Value: params.LargeArea,
+		Name: master.Name("large_area", params.LargeArea), Options: master.LargeAreas,
+	})
+	if params.LargeArea != "" || params.MiddleArea != "" {
+		options := master.MiddleAreasIn(params.LargeArea)
+		if params.LargeArea == "" {
+			options = master.MiddleAreas
+		}
+		view.Selects = append(view.Selects, selectChip{
+			Field: "middle_area", Label: "地域", Value: params.MiddleArea,
+			Name: master.Name("middle_area", params.MiddleArea), Options: options,
+		})
+	}
+	if params.MiddleArea != "" || params.SmallArea != "" {
+		options := master.SmallAreasIn(params.MiddleArea)
+		if params.MiddleArea == "" {
+			options = master.SmallAreas
+		}
+		view.Selects = append(view.Selects, selectChip{
+			Field: "small_area", Label: "駅・街", Value: params.SmallArea,
+			Name: master.Name("small_area", params.SmallArea), Options: options,
+		})
+	}
+	view.Selects = append(view.Selects,
+		selectChip{Field: "genre", Label: "ジャンル", Value: params.Genre, Name: master.Name("genre", params.Genre), Options: master.Genres},
+		selectChip{Field: "budget", Label: "予算", Value: params.Budget, Name: master.Name("budget", params.Budget), Options: master.Budgets},
+	)
+
+	for _, flag := range entity.FacilityOptions {
+		if params.RequiresFacility(flag.Name) {
+			view.Flags = append(view.Flags, flag)
+		} else {
+			view.AvailableFlags = append(view.AvailableFlags, flag)
+		}
+	}
+	return view
+}
+
+// paramsFromForm はチップのフォームから検索条件を作成し、削除・追加の操作を反映します
+func paramsFromForm(c *gin.Context) *entity.SearchCriteria {
+	params := &entity.SearchCriteria{
+		Keyword:    formValue(c, "keyword"),
+		LargeArea:  formValue(c, "large_area"),
+		MiddleArea: formValue(c, "middle_area"),
+		SmallArea:  formValue(c, "small_area"),
+		Genre:      formValue(c, "genre"),
+		Budget:     formValue(c, "budget"),
+	}
+	params.Lat, params.Lng = locationFromForm(c)
+	if n, err := strconv.Atoi(formValue(c, "range")); err == nil && n >= 1 && n <= 5 {
+		params.RadiusMeters = entity.RangeMeters(n)
+	}
+	if n, err := strconv.Atoi(formValue(c, "party_capacity")); err == nil && n > 0 {
+		params.PartyCapacity = n
+	}
+	for _, flag := range entity.FacilityOptions {
+		if formValue(c, flag.Name) == "1" {
+			params.SetFacility(flag.Name, true)
+		}
+	}
+	if field := formValue(c, "add_flag"); entity.IsFacility(field) {
+		params.SetFacility(field, true)
+	}
+
+	// サイドバーで選択したファセット（"genre:G001" / "budget:B003" / "flags:private_room"）
+	if field, value, ok := strings.Cut(formValue(c, "facet"), ":"); ok {
+		switch field {
+		case "genre":
+			params.Genre = value
+		case "budget":
+			params.Budget = value
+		case "flags":
+			if entity.IsFacility(value) {
+				params.SetFacility(value, true)
+			}
+		}
+	}
+
+	// × ボタンで削除された条件（上位のエリアを削除した場合は下位のエリアも削除する）
+	switch field := formValue(c, "remove"); field {
+	case "large_area":
+		params.LargeArea, params.MiddleArea, params.SmallArea = "", "", ""
+	case "middle_area":
+		params.MiddleArea, params.SmallArea = "", ""
+	case "small_area":
+		params.SmallArea = ""
+	case "genre":
+		params.Genre = ""
+	case "budget":
+		params.Budget = ""
+	case "keyword":
+		params.Keyword = ""
+	case "party_capacity":
+		params.PartyCapacity = 0
+	case "location":
+		params.Lat, params.Lng, params.RadiusMeters = 0, 0, 0
+	default:
+		params.SetFacility(field, false)
+	}
+	return params
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"restaurant-finder/Domain/entity"
+)
+
+// newFormContext はフォームを POST したリクエストの Context を作成します
+func newFormContext(form url.Values) *gin.Context {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPost, "/search", strings.NewReader(form.Encode()))
+	c.Request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return c
+}
+
+func TestParamsFromForm(t *testing.T) {
+	areas := url.Values{"mode": {"params"}, "large_area": {"Z011"}, "middle_area": {"Y005"}, "small_area": {"X001"}, "genre": {"G001"}}
+	with := func(base url.Values, kv ...string) url.Values {
+		form := url.Values{}
+		for k, v := range base {
+			form[k] = v
+		}
+		for i := 0; i+1 < len(kv); i += 2 {
+			form.Set(kv[i], kv[i+1])
+		}
+		return form
+	}
+
+	tests := []struct {
+		name string
+		form url.Values
+		want entity.SearchCriteria
+	}{
+		{
+			name: "条件をそのまま読む",
+			form: with(areas, "keyword", "焼き鳥", "budget", "B003", "party_capacity", "6", "lunch", "1"),
+			want: entity.SearchCriteria{Keyword: "焼き鳥", LargeArea: "Z011", MiddleArea: "Y005", SmallArea: "X001", Genre: "G001", Budget: "B003",
+				PartyCapacity: 6, Facilities: []string{entity.FacilityLunch}},
+		},
+		{
+			name: "条件を追加",
+			form: url.Values{"lunch": {"1"}, "add_flag": {entity.FacilityPrivateRoom}},
+			want: entity.SearchCriteria{Facilities: []string{entity.FacilityLunch, entity.FacilityPrivateRoom}},
+		},
+		{
+			name: "一覧にない条件は追加しない",
+			form: url.Values{"add_flag": {"key"}},
+			want: entity.SearchCriteria{},
+		},
+		{
+			name: "条件を削除",
+			form: url.Values{"lunch": {"1"}, "wine": {"1"}, "remove": {entity.FacilityLunch}},
+			want: entity.SearchCriteria{Facilities: []string{entity.FacilityWine}},
+		},
+		{
+			name: "大エリアを削除すると下位のエリアも削除",
+			form: with(areas, "remove", "large_area"),
+			want: entity.SearchCriteria{Genre: "G001"},
+		},
+		{
+			name: "中エリアを削除すると小エリアも削除",
+			form: with(areas, "remove", "middle_area"),
+			want: entity.SearchCriteria{LargeArea: "Z011", Genre: "G001"},
+		},
+		{
+			name: "小エリアだけを削除",
+			form: with(areas, "remove", "small_area"),
+			want: entity.SearchCriteria{LargeArea: "Z011", MiddleArea: "Y005", Genre: "G001"},
+		},
+		{
+			name: "ファセットでジャンルを選択",
+			form: with(areas, "facet", "genre:G002"),
+			want: entity.SearchCriteria{LargeArea: "Z011", MiddleArea: "Y005", SmallArea: "X001", Genre: "G002"},
+		},
+		{
+			name: "ファセットでこだわり条件を選択",
+			form: url.Values{"sake": {"1"}, "facet": {"flags:" + entity.FacilityPrivateRoom}},
+			want: entity.SearchCriteria{Facilities: []string{entity.FacilitySake, entity.FacilityPrivateRoom}},
+		},
+		{
+			name: "現在地と範囲",
+			form: url.Values{"lat": {"35.6896"}, "lng": {"139.7006"}, "range": {"2"}},
+			want: entity.SearchCriteria{Lat: 35.6896, Lng: 139.7006, RadiusMeters: 500},
+		},
+		{
+			name: "現在地を削除",
+			form: url.Values{"lat": {"35.6896"}, "lng": {"139.7006"}, "range": {"2"}, "remove": {"location"}},
+			want: entity.SearchCriteria{},
+		},
+	}
+	for _, tt := range tests {
+		got := paramsFromForm(newFormContext(tt.form))
+		if !reflect.DeepEqual(*got, tt.want) {
+			t.Errorf("%s: params = %+v, want %+v", tt.name, *got, tt.want)
+		}
+	}
+}
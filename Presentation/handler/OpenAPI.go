@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// openAPIVersion は API のバージョンです（パスの /api/v1 と対応します）
+const openAPIVersion = "1.0.0"
+
+// OpenAPIDocument は APIV1Routes とリクエスト・レスポンスの型から OpenAPI 3.0 のドキュメントを生成します
+func OpenAPIDocument() map[string]any {
+	schemas := map[string]any{}
+	errorSchema := schemaFor(reflect.TypeOf(apiErrorResponse{}), schemas)
+
+	paths := map[string]any{}
+	// ドキュメントにはルートの情報だけを使うため、依存のないハンドラで一覧を作る
+	for _, route := range APIV1Routes(&APIHandler{}) {
+		operation := map[string]any{
+			"summary":     route.Summary,
+			"operationId": operationID(route),
+			"responses": map[string]any{
+				"200": jsonContent("成功", schemaFor(reflect.TypeOf(route.Response), schemas)),
+			},
+		}
+		if len(route.Params) > 0 {
+			params := make([]any, 0, len(route.Params))
+			for _, p := range route.Params {
+				schema := map[string]any{"type": "string"}
+				if len(p.Enum) > 0 {
+					schema["enum"] = p.Enum
+				}
+				params = append(params, map[string]any{
+					"name":        p.Name,
+					"in":          p.In,
+					"description": p.Description,
+					"required":    p.Required || p.In == "path",
+					"schema":      schema,
+				})
+			}
+			operation["parameters"] = params
+		}
+		if route.Request != nil {
+			operation["requestBody"] = map[string]any{
+				"required": true,
+				"content": map[string]any{
+					"application/json": map[string]any{"schema": schemaFor(reflect.TypeOf(route.Request), schemas)},
+				},
+			}
+		}
+		responses := operation["responses"].(map[string]any)
+		for _, status := range route.Errors {
+			responses[strconv.Itoa(status)] = jsonContent(http.StatusText(status), errorSchema)
+		}
+
+		path := openAPIPath(APIV1Prefix + route.Path)
+		item, ok := paths[path].(map[string]any)
+		if !ok {
+			item = map[string]any{}
+			paths[path] = item
+		}
+		item[strings.ToLower(route.Method)] = operation
+	}
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":       "Restaurant Finder API",
+			"description": "自然文のクエリまたは検索条件で HotPepper のレストランを検索する API です。エラーはすべて {\"error\": {\"code\", \"message\"}} の形式で返します",
+			"version":     openAPIVersion,
+		},
+		"paths":      paths,
+		"components": map[string]any{"schemas": schemas},
+	}
+}
+
+// jsonContent は JSON のレスポンスの定義を作成します
+func jsonContent(description string, schema map[string]any) map[string]any {
+	return map[string]any{
+		"description": description,
+		"content": map[string]any{
+			"application/json": map[string]any{"schema": schema},
+		},
+	}
+}
+
+// openAPIPath は gin のパス（/shops/:id）を OpenAPI の形式（/shops/{id}）にします
+func openAPIPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// operationID はハンドラを特定する operationId（"getShopsId" など）を作成します
+func operationID(route APIRoute) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(route.Method))
+	for _, segment := range strings.FieldsFunc(route.Path, func(r rune) bool { return r == '/' || r == ':' || r == '.' }) {
+		b.WriteString(strings.ToUpper(segment[:1]) + segment[1:])
+	}
+	return b.String()
+}
+
+// schemaName は型からスキーマ名を作成します（"apiSearchRequest" は "SearchRequest"）
+func schemaName(t reflect.Type) string {
+	name := strings.TrimPrefix(t.Name(), "api")
+	runes := []rune(name)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// schemaFor は型の JSON スキーマを返します。名前のある構造体は components に登録して参照を返します
+func schemaFor(t reflect.Type, schemas map[string]any) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == reflect.TypeOf(time.Time{}) {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": schemaFor(t.Elem(), schemas)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": schemaFor(t.Elem(), schemas)}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return structSchema(t, schemas)
+		}
+		name := schemaName(t)
+		if _, ok := schemas[name]; !ok {
+			// 再帰的な型に備えて先に登録する
+			schemas[name] = map[string]any{}
+			schemas[name] = structSchema(t, schemas)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + name}
+	}
+	// interface{} など型が決まらないもの
+	return map[string]any{}
+}
+
+// structSchema は構造体のフィールドを json タグに従ってスキーマにします（omitempty のないフィールドは必須）
+func structSchema(t reflect.Type, schemas map[string]any) map[string]any {
+	properties := map[string]any{}
+	required := make([]string, 0)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = schemaFor(field.Type, schemas)
+		if !strings.Contains(options, "omitempty") {
+			required = append(required, name)
+		}
+	}
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"restaurant-finder/Application/usecase"
+	"restaurant-finder/Domain/entity"
+)
+
+const (
+	// maxPage は共有 URL で指定できる最大のページです
+	maxPage = 100
+	// pageWindow は前後に表示するページ番号の数です
+	pageWindow = 2
+	// ogShopCount は OpenGraph の説明に載せる店舗の数です
+	ogShopCount = 3
+)
+
+// pageLink はページ送りのリンクです
+type pageLink struct {
+	Number  int
+	URL     string
+	Current bool
+}
+
+// pagination は検索結果のページ送りです
+type pagination struct {
+	Prev  string
+	Next  string
+	Pages []pageLink
+}
+
+// formValue は POST のフォーム、なければ URL のクエリパラメータから値を取り出す（GET の共有 URL に対応するため）
+func formValue(c *gin.Context, key string) string {
+	if v, ok := c.GetPostForm(key); ok {
+		return v
+	}
+	return c.Query(key)
+}
+
+// pageFromForm は表示するページ（1 から）を取り出す
+func pageFromForm(c *gin.Context) int {
+	page, err := strconv.Atoi(formValue(c, "page"))
+	if err != nil || page < 1 {
+		return 1
+	}
+	return min(page, maxPage)
+}
+
+// shareValues は検索結果を再現する GET /search のクエリパラメータを作成する
+// クエリで検索した場合は、解決したエリア・ジャンルのコードを候補の選択として付けて同じ条件で検索されるようにする
+// チップで編集した検索条件の場合は、編集した条件をそのまま付ける
+func shareValues(c *gin.Context, prompt string, result *usecase.GetRestaurantResult, edited bool, origin string) url.Values {
+	values := url.Values{}
+	if prompt != "" {
+		values.Set("q", prompt)
+	}
+	params := result.SearchParams
+	if params == nil {
+		params = &entity.SearchCriteria{}
+	}
+
+	if edited {
+		values.Set("mode", "params")
+		setIfNotEmpty(values, "keyword", params.Keyword)
+		setIfNotEmpty(values, "budget", params.Budget)
+		if params.PartyCapacity > 0 {
+			values.Set("party_capacity", strconv.Itoa(params.PartyCapacity))
+		}
+		for _, facility := range params.Facilities {
+			if entity.IsFacility(facility) {
+				values.Set(facility, "1")
+			}
+		}
+		if params.Lat != 0 || params.Lng != 0 {
+			values.Set("lat", strconv.FormatFloat(params.Lat, 'f', -1, 64))
+			values.Set("lng", strconv.FormatFloat(params.Lng, 'f', -1, 64))
+			values.Set("range", strconv.Itoa(entity.RangeForMeters(params.RadiusMeters)))
+			setIfNotEmpty(values, "origin", origin)
+		}
+	} else {
+		for _, key := range []string{"lat", "lng", "meet_stations"} {
+			setIfNotEmpty(values, key, formValue(c, key))
+		}
+	}
+	setIfNotEmpty(values, "large_area", params.LargeArea)
+	setIfNotEmpty(values, "middle_area", params.MiddleArea)
+	setIfNotEmpty(values, "small_area", params.SmallArea)
+	setIfNotEmpty(values, "genre", params.Genre)
+
+	if formValue(c, "open_now") == "1" {
+		values.Set("open_now", "1")
+	} else {
+		setIfNotEmpty(values, "open_at", formValue(c, "open_at"))
+	}
+	if result.MaxWalkMinutes > 0 {
+		values.Set("walk_minutes", strconv.Itoa(result.MaxWalkMinutes))
+	}
+	if result.SortByWalk {
+		values.Set("sort", "walk")
+	}
+	return values
+}
+
+// setIfNotEmpty は値が空でない場合だけクエリパラメータに設定する
+func setIfNotEmpty(values url.Values, key, value string) {
+	if value != "" {
+		values.Set(key, value)
+	}
+}
+
+// searchURL は指定したページの GET /search の URL を作成する
+func searchURL(values url.Values, page int) string {
+	v := url.Values{}
+	for key, value := range values {
+		v[key] = value
+	}
+	if page > 1 {
+		v.Set("page", strconv.Itoa(page))
+	}
+	return "/search?" + v.Encode()
+}
+
+// absoluteURL はリクエストのホストから絶対 URL を作成する（OpenGraph 用）
+func absoluteURL(c *gin.Context, path string) string {
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host + path
+}
+
+// newPagination は検索結果の件数からページ送りのリンクを作成する
+func newPagination(values url.Values, page, available, count int) *pagination {
+	if count <= 0 {
+		count = 10
+	}
+	total := min((available+count-1)/count, maxPage)
+	if total <= 1 {
+		return nil
+	}
+	p := &pagination{}
+	if page > 1 {
+		p.Prev = searchURL(values, page-1)
+	}
+	if page < total {
+		p.Next = searchURL(values, page+1)
+	}
+	for n := max(1, page-pageWindow); n <= min(total, page+pageWindow); n++ {
+		p.Pages = append(p.Pages, pageLink{Number: n, URL: searchURL(values, n), Current: n == page})
+	}
+	return p
+}
+
+// ogDescription は共有したリンクのプレビューに表示する説明（件数と上位の店舗名）を作成する
+func ogDescription(shops []entity.Restaurant, available int) string {
+	if len(shops) == 0 {
+		return "条件に一致するお店は見つかりませんでした"
+	}
+	names := make([]string, 0, ogShopCount)
+	for _, shop := range shops[:min(ogShopCount, len(shops))] {
+		names = append(names, shop.Name)
+	}
+	return fmt.Sprintf("%d件のお店が見つかりました: %s", available, strings.Join(names, "、"))
+}
+
+// ogImage は共有したリンクのプレビューに表示する画像（最初の店舗の写真）を返す
+func ogImage(shops []entity.Restaurant) string {
+	for _, shop := range shops {
+		if shop.PhotoURL != "" {
+			return shop.PhotoURL
+		}
+	}
+	return ""
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+
+	"restaurant-finder/Application/usecase"
+	"restaurant-finder/Domain/entity"
+)
+
+func TestShareValues(t *testing.T) {
+	resolved := &entity.SearchCriteria{Keyword: "焼き鳥", LargeArea: "Z011", MiddleArea: "Y005", SmallArea: "X001", Genre: "G001",
+		Budget: "B003", PartyCapacity: 6, Facilities: []string{entity.FacilityLunch, "unknown"},
+		Lat: 35.6896, Lng: 139.7006, RadiusMeters: 500}
+
+	tests := []struct {
+		name   string
+		form   url.Values
+		prompt string
+		result *usecase.GetRestaurantResult
+		edited bool
+		origin string
+		want   url.Values
+	}{
+		{
+			name:   "クエリで検索した場合は解決したコードを付ける",
+			form:   url.Values{"q": {"新宿 焼き鳥"}, "lat": {"35.6896"}, "lng": {"139.7006"}, "meet_stations": {"新宿,渋谷"}},
+			prompt: "新宿 焼き鳥",
+			result: &usecase.GetRestaurantResult{SearchParams: resolved},
+			want: url.Values{"q": {"新宿 焼き鳥"}, "lat": {"35.6896"}, "lng": {"139.7006"}, "meet_stations": {"新宿,渋谷"},
+				"large_area": {"Z011"}, "middle_area": {"Y005"}, "small_area": {"X001"}, "genre": {"G001"}},
+		},
+		{
+			name:   "編集した検索条件はそのまま付ける",
+			result: &usecase.GetRestaurantResult{SearchParams: resolved},
+			edited: true,
+			origin: "新宿駅",
+			want: url.Values{"mode": {"params"}, "keyword": {"焼き鳥"}, "budget": {"B003"}, "party_capacity": {"6"}, entity.FacilityLunch: {"1"},
+				"lat": {"35.6896"}, "lng": {"139.7006"}, "range": {"2"}, "origin": {"新宿駅"},
+				"large_area": {"Z011"}, "middle_area": {"Y005"}, "small_area": {"X001"}, "genre": {"G001"}},
+		},
+		{
+			name:   "編集した検索条件に位置がなければ範囲と中心を付けない",
+			result: &usecase.GetRestaurantResult{SearchParams: &entity.SearchCriteria{Keyword: "ラーメン"}},
+			edited: true,
+			origin: "現在地",
+			want:   url.Values{"mode": {"params"}, "keyword": {"ラーメン"}},
+		},
+		{
+			name:   "営業中と駅からの徒歩",
+			form:   url.Values{"open_now": {"1"}, "open_at": {"2025-06-04T19:00"}},
+			prompt: "ラーメン",
+			result: &usecase.GetRestaurantResult{MaxWalkMinutes: 5, SortByWalk: true},
+			want:   url.Values{"q": {"ラーメン"}, "open_now": {"1"}, "walk_minutes": {"5"}, "sort": {"walk"}},
+		},
+		{
+			name:   "営業日時",
+			form:   url.Values{"open_at": {"2025-06-04T19:00"}},
+			prompt: "ラーメン",
+			result: &usecase.GetRestaurantResult{},
+			want:   url.Values{"q": {"ラーメン"}, "open_at": {"2025-06-04T19:00"}},
+		},
+	}
+	for _, tt := range tests {
+		got := shareValues(newFormContext(tt.form), tt.prompt, tt.result, tt.edited, tt.origin)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: shareValues() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNewPagination(t *testing.T) {
+	values := url.Values{"q": {"ラーメン"}}
+	tests := []struct {
+		name       string
+		page       int
+		available  int
+		prev, next string
+		pages      []int
+	}{
+		{"1 ページに収まる", 1, 10, "", "", nil},
+		{"最初のページ", 1, 45, "", "/search?page=2&q=%E3%83%A9%E3%83%BC%E3%83%A1%E3%83%B3", []int{1, 2, 3}},
+		{"途中のページ", 4, 100, "/search?page=3&q=%E3%83%A9%E3%83%BC%E3%83%A1%E3%83%B3", "/search?page=5&q=%E3%83%A9%E3%83%BC%E3%83%A1%E3%83%B3", []int{2, 3, 4, 5, 6}},
+		{"最後のページ", 5, 45, "/search?page=4&q=%E3%83%A9%E3%83%BC%E3%83%A1%E3%83%B3", "", []int{3, 4, 5}},
+		{"2 ページ目から 1 ページ目へは page を付けない", 2, 20, "/search?q=%E3%83%A9%E3%83%BC%E3%83%A1%E3%83%B3", "", []int{1, 2}},
+		{"ページ数の上限", maxPage, 5000, "/search?page=99&q=%E3%83%A9%E3%83%BC%E3%83%A1%E3%83%B3", "", []int{98, 99, 100}},
+	}
+	for _, tt := range tests {
+		p := newPagination(values, tt.page, tt.available, 10)
+		if tt.pages == nil {
+			if p != nil {
+				t.Errorf("%s: pagination = %+v, want nil", tt.name, p)
+			}
+			continue
+		}
+		if p == nil {
+			t.Errorf("%s: pagination = nil", tt.name)
+			continue
+		}
+		numbers := make([]int, 0, len(p.Pages))
+		for _, link := range p.Pages {
+			numbers = append(numbers, link.Number)
+			if link.Current != (link.Number == tt.page) {
+				t.Errorf("%s: ページ %d の Current = %t", tt.name, link.Number, link.Current)
+			}
+		}
+		if p.Prev != tt.prev || p.Next != tt.next || !reflect.DeepEqual(numbers, tt.pages) {
+			t.Errorf("%s: pagination = prev %q next %q pages %v, want prev %q next %q pages %v",
+				tt.name, p.Prev, p.Next, numbers, tt.prev, tt.next, tt.pages)
+		}
+	}
+}
@@ -1,30 +1,93 @@
 package handler
 
 import (
+	"encoding/json"
+	"errors"
+	"log"
 	"net/http"
 	"github.com/gin-gonic/gin"
 	"restaurant-finder/Application/usecase"
+	"restaurant-finder/Domain/entity"
 )
 
-// SearchHandler 検索ページを表示
-func SearchHandler(c *gin.Context) {
+// RestaurantHandler 検索ページと検索結果を表示するハンドラ
+type RestaurantHandler struct {
+	search     *usecase.GetRestaurantUsecase
+	masterData *usecase.MasterDataUsecase
+}
+
+// NewRestaurantHandler RestaurantHandlerのコンストラクタ
+func NewRestaurantHandler(search *usecase.GetRestaurantUsecase, masterData *usecase.MasterDataUsecase) *RestaurantHandler {
+	return &RestaurantHandler{search: search, masterData: masterData}
+}
+
+// Search 検索ページを表示
+func (h *RestaurantHandler) Search(c *gin.Context) {
 	c.HTML(http.StatusOK, "search.html", nil)
 }
 
-// ProcessSearchHandler 検索リクエストを処理し、結果を表示
-func ProcessSearchHandler(c *gin.Context) {
-	// フォームから検索クエリを取得
-	prompt := c.PostForm("search_query")
+// ProcessSearch 検索リクエストを処理し、結果を表示
+func (h *RestaurantHandler) ProcessSearch(c *gin.Context) {
+	// フォームから検索クエリを取得（GET の共有 URL では q）
+	prompt := formValue(c, "search_query")
 	if prompt == "" {
+		prompt = c.Query("q")
+	}
+	// チップで編集した検索条件での再検索（LLM を使わない）
+	var editedParams *entity.SearchCriteria
+	if formValue(c, "mode") == "params" {
+		editedParams = paramsFromForm(c)
+	}
+	if prompt == "" && editedParams == nil && c.Request.Method == http.MethodGet {
+		// 条件のない GET /search は検索ページを表示する
+		h.Search(c)
+		return
+	}
+	if prompt == "" && editedParams == nil {
 		c.HTML(http.StatusBadRequest, "search.html", gin.H{
 			"error": "検索クエリを入力してください",
 		})
 		return
 	}
 
-	// ユースケースを作成して検索を実行、usecaseのメソッド呼び出し
-	usecase := usecase.NewGetRestaurantUsecase()
-	result, err := usecase.GetRestaurantWithNaturalLanguage(prompt)
+	// 候補から選択されたコード（曖昧なエリア・ジャンルの確認後の再検索）
+	var overrides map[string]string
+	if editedParams == nil {
+		overrides = overridesFromForm(c)
+	}
+	// 変換過程（explain）の表示
+	explain := formValue(c, "explain") == "1"
+	// 営業時間での絞り込み
+	openAt, openNow, openAtText := openFilterFromForm(c)
+	// ブラウザで取得した現在地と、中間地点で探す場合の集合駅
+	lat, lng := locationFromForm(c)
+	meetStations := meetStationsFromForm(c)
+	// 駅からの徒歩の上限と並び順
+	maxWalkMinutes, sortByWalk := walkFilterFromForm(c)
+	// 表示するページ（共有 URL のページ送り）
+	page := pageFromForm(c)
+
+	// 検索を実行、usecaseのメソッド呼び出し
+	result, err := h.search.Search(usecase.SearchRequest{
+		Prompt:         prompt,
+		ClientID:       c.ClientIP(),
+		Overrides:      overrides,
+		Params:         editedParams,
+		OpenAt:         openAt,
+		Lat:            lat,
+		Lng:            lng,
+		MeetStations:   meetStations,
+		MaxWalkMinutes: maxWalkMinutes,
+		SortByWalk:     sortByWalk,
+		Page:           page,
+	})
+	var rejected *usecase.InputRejectedError
+	if errors.As(err, &rejected) {
+		c.HTML(http.StatusBadRequest, "search.html", gin.H{
+			"error": rejected.Message,
+		})
+		return
+	}
 	if err != nil {
 		c.HTML(http.StatusInternalServerError, "search.html", gin.H{
 			"error": "検索中にエラーが発生しました: " + err.Error(),
@@ -32,12 +95,157 @@ func ProcessSearchHandler(c *gin.Context) {
 		return
 	}
 
+	// エリアやジャンルが一意に決まらない場合は候補を表示して選んでもらう
+	if result.NeedsClarification {
+		c.HTML(http.StatusOK, "search.html", gin.H{
+			"query":          prompt,
+			"overrides":      overrides,
+			"clarifications": result.Clarifications,
+			"openNow":        openNow,
+			"openAt":         openAtText,
+			"lat":            formValue(c, "lat"),
+			"lng":            formValue(c, "lng"),
+			"meetStations":   formValue(c, "meet_stations"),
+			"walkMinutes":    maxWalkMinutes,
+			"explain":        explain,
+			"trace":          result.Trace,
+			"traceJSON":      traceJSON(result),
+		})
+		return
+	}
+
+	// クエリから読み取った来店日時で絞り込んだ場合もチップに表示する
+	if result.OpenAt != nil && openAt == nil {
+		openAtText = result.OpenAt.Format(openAtLayout)
+	}
+	master, err := h.masterData.MasterData()
+	if err != nil {
+		log.Printf("master data unavailable: %v", err)
+	}
+	filters := newFilterView(result.SearchParams, master)
+	// 距離の基準にした地点（チップで再検索した場合はフォームに保持した名前）
+	origin := result.Origin
+	if origin == "" {
+		origin = formValue(c, "origin")
+	}
+	var latText, lngText string
+	if filters != nil {
+		filters.OpenNow, filters.OpenAt = openNow, openAtText
+		if filters.Lat != "" && origin == "" {
+			origin = "現在地"
+		}
+		filters.Origin = origin
+		filters.MaxWalkMinutes, filters.SortByWalk = result.MaxWalkMinutes, result.SortByWalk
+		latText, lngText = filters.Lat, filters.Lng
+	}
+
+	// 検索結果を再現する共有 URL とページ送り
+	shopsFound := result.Page.Restaurants
+	available := result.Page.Available
+	values := shareValues(c, prompt, result, editedParams != nil, origin)
+	var count int
+	if result.SearchParams != nil {
+		count = result.SearchParams.Count
+	}
+
 	// 検索結果をテンプレートに渡す
 	//検索ワード、検索件数、自然言語での説明
 	c.HTML(http.StatusOK, "search.html", gin.H{
-		"restaurants":        result.Response.Results.Shop,
+		"restaurants":        shopsFound,
 		"query":              prompt,
-		"count":              result.Response.Results.ResultsReturned,
+		"count":              result.Page.Returned(),
 		"naturalDescription": result.NaturalDescription,
+		"tokenUsage":         result.TokenUsage,
+		"llmSkipped":         result.LLMSkipped,
+		"ambiguities":        result.Ambiguities,
+		"relaxations":        result.Relaxations,
+		"facets":             result.Facets,
+		"facetScanned":       result.FacetScanned,
+		"facetTotal":         result.FacetTotal,
+		"filters":            filters,
+		"openNow":            openNow,
+		"openAt":             openAtText,
+		"unknownHours":       result.UnknownHours,
+		"distances":          formatDistances(result.Distances),
+		"origin":             origin,
+		"unknownStations":    result.UnknownStations,
+		"meetStations":       formValue(c, "meet_stations"),
+		"lat":                latText,
+		"lng":                lngText,
+		"overrides":          overrides,
+		"explain":            explain,
+		"trace":              result.Trace,
+		"traceJSON":          traceJSON(result),
+		"page":               page,
+		"pagination":         newPagination(values, page, available, count),
+		"shareURL":           absoluteURL(c, searchURL(values, page)),
+		"ogDescription":      ogDescription(shopsFound, available),
+		"ogImage":            ogImage(shopsFound),
+	})
+}
+
+// Explain 検索リクエストを処理し、検索条件の変換過程を JSON で返す
+func (h *RestaurantHandler) Explain(c *gin.Context) {
+	prompt := formValue(c, "search_query")
+	if prompt == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "検索クエリを入力してください"})
+		return
+	}
+
+	openAt, _, _ := openFilterFromForm(c)
+	lat, lng := locationFromForm(c)
+	result, err := h.search.Search(usecase.SearchRequest{
+		Prompt:       prompt,
+		ClientID:     c.ClientIP(),
+		Overrides:    overridesFromForm(c),
+		OpenAt:       openAt,
+		Lat:          lat,
+		Lng:          lng,
+		MeetStations: meetStationsFromForm(c),
+	})
+	var rejected *usecase.InputRejectedError
+	if errors.As(err, &rejected) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": rejected.Message})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "検索中にエラーが発生しました: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"query":               prompt,
+		"search_params":       result.SearchParams,
+		"extraction_tier":     result.ExtractionTier,
+		"language":            result.Language,
+		"needs_clarification": result.NeedsClarification,
+		"relaxations":         result.Relaxations,
+		"open_at":             result.OpenAt,
+		"origin":              result.Origin,
+		"max_walk_minutes":    result.MaxWalkMinutes,
+		"trace":               result.Trace,
 	})
 }
+
+// overridesFromForm はフォームから候補の選択（エリア・ジャンルのコード）を取り出す
+func overridesFromForm(c *gin.Context) map[string]string {
+	overrides := make(map[string]string)
+	for _, key := range []string{"large_area", "middle_area", "small_area", "genre"} {
+		if v := formValue(c, key); v != "" {
+			overrides[key] = v
+		}
+	}
+	return overrides
+}
+
+// traceJSON は変換過程を表示用に整形した JSON 文字列にする
+func traceJSON(result *usecase.GetRestaurantResult) string {
+	if result.Trace == nil {
+		return ""
+	}
+	data, err := json.MarshalIndent(result.Trace, "", "  ")
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
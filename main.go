@@ -1,8 +1,12 @@
 package main
 
 import (
+	"encoding/json"
+	"flag"
 	"log"
 	"os"
+	"restaurant-finder/Application/usecase"
+	api "restaurant-finder/Infrastructure/api"
 	"restaurant-finder/Presentation/handler"
 
 	"github.com/gin-gonic/gin"
@@ -12,6 +16,14 @@ import (
 var hotpepperAPIKey string
 
 func main() {
+	// -openapi を指定した場合は OpenAPI ドキュメントをファイルに書き出して終了する
+	openapiPath := flag.String("openapi", "", "OpenAPI ドキュメントを書き出すファイル")
+	flag.Parse()
+	if *openapiPath != "" {
+		writeOpenAPIDocument(*openapiPath)
+		return
+	}
+
 	err := godotenv.Load()
 	if err != nil {
 		log.Printf("Warning: Error loading .env file: %v", err)
@@ -29,6 +41,33 @@ func main() {
 
 	log.Printf("Environment variables loaded successfully")
 
+	// 外部サービスのクライアントとユースケースは起動時に一度だけ作成し、ハンドラに渡す
+	synonyms := api.NewSynonymStoreFromEnv()
+	generator := api.NewOpenAIGenerator(openaiAPIKey).
+		WithModels(api.LoadModelConfigFromEnv()).
+		WithQueryCache(api.NewQueryCacheFromEnv()).
+		WithSynonyms(synonyms)
+	masterData := api.NewFormatMasterDataRepository()
+	// 店舗はホットペッパーとチームのお気に入りの CSV から同時に検索し、同じ店舗を 1 件にまとめる
+	sources := api.NewSourceRegistry(
+		api.NewHotPepperAPIClient(hotpepperAPIKey),
+		api.NewFavoritesSourceFromEnv(),
+	)
+	searchUsecase := usecase.NewGetRestaurantUsecase(
+		sources,
+		generator,
+		generator,
+		api.NewUsageTrackerFromEnv(),
+		api.NewStationGazetteerFromEnv(),
+		masterData,
+		api.NewQueryConditionExtractor(),
+	)
+	masterDataUsecase := usecase.NewMasterDataUsecase(masterData)
+
+	restaurantHandler := handler.NewRestaurantHandler(searchUsecase, masterDataUsecase)
+	apiHandler := handler.NewAPIHandler(searchUsecase, masterDataUsecase)
+	adminHandler := handler.NewAdminHandler(usecase.NewSynonymUsecase(synonyms))
+
 	router := gin.Default()
 
 	// 静的ファイルの設定
@@ -36,8 +75,34 @@ func main() {
 	router.LoadHTMLGlob("templates/*.html")
 
 	// ルートの設定
-	router.GET("/", handler.SearchHandler)
-	router.POST("/search", handler.ProcessSearchHandler)
+	router.GET("/", restaurantHandler.Search)
+	router.GET("/search", restaurantHandler.ProcessSearch)
+	router.POST("/search", restaurantHandler.ProcessSearch)
+	router.POST("/explain", restaurantHandler.Explain)
+
+	// JSON API（/api/v1）
+	v1 := router.Group(handler.APIV1Prefix)
+	for _, route := range handler.APIV1Routes(apiHandler) {
+		v1.Handle(route.Method, route.Path, route.Handler)
+	}
+	router.NoRoute(handler.NotFoundHandler)
+
+	// 同義語辞書の管理ページ（ADMIN_USER / ADMIN_PASSWORD を設定した場合のみ有効）
+	if err := handler.RegisterAdminRoutes(router, adminHandler, os.Getenv("ADMIN_USER"), os.Getenv("ADMIN_PASSWORD")); err != nil {
+		log.Printf("%v", err)
+	}
 
 	router.Run(":8080")
 }
+
+// writeOpenAPIDocument は JSON API の OpenAPI ドキュメントを書き出す
+func writeOpenAPIDocument(path string) {
+	data, err := json.MarshalIndent(handler.OpenAPIDocument(), "", "  ")
+	if err != nil {
+		log.Fatalf("OpenAPI ドキュメントを生成できません: %v", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		log.Fatalf("OpenAPI ドキュメントを書き出せません: %v", err)
+	}
+	log.Printf("OpenAPI ドキュメントを書き出しました: %s", path)
+}